@@ -0,0 +1,126 @@
+package cache
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/dgraph-io/badger/v4"
+)
+
+// Record is a cache entry with its key.
+type Record struct {
+	Key string `json:"key"`
+	Entry
+}
+
+// errStop ends an iteration early.
+var errStop = errors.New("stop iteration")
+
+// Iterate calls fn for each entry in key order until fn returns false.
+func (c *Cache) Iterate(fn func(Record) bool) error {
+	err := c.db.View(func(txn *badger.Txn) error {
+		it := txn.NewIterator(badger.DefaultIteratorOptions)
+		defer it.Close()
+
+		for it.Rewind(); it.Valid(); it.Next() {
+			item := it.Item()
+			if isMeta(item.Key()) {
+				continue
+			}
+			rec := Record{Key: string(item.Key())}
+			err := item.Value(func(val []byte) error {
+				return json.Unmarshal(val, &rec.Entry)
+			})
+			if err != nil {
+				return fmt.Errorf("decode entry %s: %w", rec.Key, err)
+			}
+			if !fn(rec) {
+				return errStop
+			}
+		}
+		return nil
+	})
+	if errors.Is(err, errStop) {
+		return nil
+	}
+	return err
+}
+
+// SearchPrefix returns up to limit entries, newest first, whose source text
+// starts with prefix. Matching ignores case and differences in whitespace.
+// A limit of 0 or less returns all matches.
+func (c *Cache) SearchPrefix(prefix string, limit int) ([]Record, error) {
+	prefix = foldText(prefix)
+	return c.search(func(e *Entry) bool {
+		return strings.HasPrefix(foldText(e.SourceText), prefix)
+	}, limit)
+}
+
+// Search returns up to limit entries, newest first, whose source text or
+// translation contains every word of query. Matching ignores case and
+// differences in whitespace; an empty query matches every entry. A limit
+// of 0 or less returns all matches.
+func (c *Cache) Search(query string, limit int) ([]Record, error) {
+	words := strings.Fields(foldText(query))
+	return c.search(func(e *Entry) bool {
+		source, text := foldText(e.SourceText), foldText(e.Text)
+		for _, w := range words {
+			if !strings.Contains(source, w) && !strings.Contains(text, w) {
+				return false
+			}
+		}
+		return true
+	}, limit)
+}
+
+// Delete removes the entry stored under key. Deleting a missing key is not
+// an error.
+func (c *Cache) Delete(key string) error {
+	if !validKey(key) {
+		return fmt.Errorf("invalid cache key %q", key)
+	}
+
+	var d delta
+	err := c.db.Update(func(txn *badger.Txn) error {
+		var err error
+		d, err = deleteEntry(txn, key)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	c.apply(d)
+	c.l1.remove(key)
+	return nil
+}
+
+// search returns the entries matching match, newest first, keeping at most
+// limit of them if limit is positive.
+func (c *Cache) search(match func(*Entry) bool, limit int) ([]Record, error) {
+	var found []Record
+	err := c.Iterate(func(rec Record) bool {
+		if match(&rec.Entry) {
+			found = append(found, rec)
+		}
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	slices.SortFunc(found, func(a, b Record) int {
+		return b.CreatedAt.Compare(a.CreatedAt)
+	})
+	if limit > 0 && len(found) > limit {
+		found = found[:limit]
+	}
+	return found, nil
+}
+
+// foldText normalizes s as for cache keys and lowercases it for matching.
+func foldText(s string) string {
+	return strings.ToLower(normalizeText(s))
+}
@@ -0,0 +1,98 @@
+package cache
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestSearch(t *testing.T) {
+	c := newTestCache(t)
+
+	now := time.Now()
+	entries := []Entry{
+		{SourceText: "Hello, world!", Text: "你好，世界！", SourceLang: "en", TargetLang: "zh", CreatedAt: now.Add(-2 * time.Hour)},
+		{SourceText: "Hello   there", Text: "你好", SourceLang: "en", TargetLang: "zh", CreatedAt: now.Add(-time.Hour)},
+		{SourceText: "Goodbye", Text: "再见", SourceLang: "en", TargetLang: "zh", CreatedAt: now},
+	}
+	keys := make(map[string]string)
+	for _, e := range entries {
+		key := GenerateKey("openai", "gpt-4", e.SourceLang, e.TargetLang, e.SourceText)
+		keys[e.SourceText] = key
+		if err := c.Set(key, &e, DefaultTTL); err != nil {
+			t.Fatalf("set: %v", err)
+		}
+	}
+
+	sources := func(recs []Record) []string {
+		var out []string
+		for _, r := range recs {
+			out = append(out, r.SourceText)
+		}
+		return out
+	}
+
+	tests := []struct {
+		name   string
+		search func() ([]Record, error)
+		want   []string
+	}{
+		{"all newest first", func() ([]Record, error) { return c.Search("", 0) }, []string{"Goodbye", "Hello   there", "Hello, world!"}},
+		{"limit", func() ([]Record, error) { return c.Search("", 1) }, []string{"Goodbye"}},
+		{"source word", func() ([]Record, error) { return c.Search("WORLD", 0) }, []string{"Hello, world!"}},
+		{"translation word", func() ([]Record, error) { return c.Search("你好", 0) }, []string{"Hello   there", "Hello, world!"}},
+		{"every word", func() ([]Record, error) { return c.Search("hello 世界", 0) }, []string{"Hello, world!"}},
+		{"prefix", func() ([]Record, error) { return c.SearchPrefix("hello there", 0) }, []string{"Hello   there"}},
+		{"prefix not infix", func() ([]Record, error) { return c.SearchPrefix("world", 0) }, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.search()
+			if err != nil {
+				t.Fatalf("search: %v", err)
+			}
+			if !slices.Equal(sources(got), tt.want) {
+				t.Errorf("got %q, want %q", sources(got), tt.want)
+			}
+		})
+	}
+
+	recs, err := c.Search("goodbye", 0)
+	if err != nil {
+		t.Fatalf("search: %v", err)
+	}
+	if len(recs) != 1 || recs[0].Key != keys["Goodbye"] {
+		t.Fatalf("record key = %v, want %s", recs, keys["Goodbye"])
+	}
+	if err := c.Delete(recs[0].Key); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if _, found := c.Get(keys["Goodbye"]); found {
+		t.Error("entry still cached after delete")
+	}
+	if err := c.Delete(recs[0].Key); err != nil {
+		t.Errorf("delete missing key: %v", err)
+	}
+}
+
+func TestIterateStops(t *testing.T) {
+	c := newTestCache(t)
+
+	for _, text := range []string{"a", "b", "c"} {
+		if err := c.Set(GenerateKey("p", "m", "en", "zh", text), &Entry{Text: text}, DefaultTTL); err != nil {
+			t.Fatalf("set: %v", err)
+		}
+	}
+
+	n := 0
+	err := c.Iterate(func(Record) bool {
+		n++
+		return n < 2
+	})
+	if err != nil {
+		t.Fatalf("iterate: %v", err)
+	}
+	if n != 2 {
+		t.Errorf("visited %d entries, want 2", n)
+	}
+}
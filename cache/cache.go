@@ -5,9 +5,11 @@ import (
 	"crypto/sha256"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"regexp"
 	"strings"
+	"sync"
 	"sync/atomic"
 	"time"
 
@@ -21,8 +23,18 @@ const DefaultTTL = 7 * 24 * time.Hour // 7 days
 // Entry represents a cached LLM response.
 type Entry struct {
 	Text      string    `json:"text"`
+	Reasoning string    `json:"reasoning,omitempty"`
 	Usage     Usage     `json:"usage"`
 	CreatedAt time.Time `json:"created_at"`
+
+	// What was translated, for browsing the cache. Keys are hashes, so these
+	// are the only record of it; entries written before they were added
+	// leave them empty.
+	SourceText string `json:"source_text,omitempty"`
+	SourceLang string `json:"source_lang,omitempty"`
+	TargetLang string `json:"target_lang,omitempty"`
+	Provider   string `json:"provider,omitempty"`
+	Model      string `json:"model,omitempty"`
 }
 
 // Usage mirrors types.Usage to avoid import cycle.
@@ -36,6 +48,10 @@ type Usage struct {
 type Stats struct {
 	Hits   uint64 `json:"hits"`
 	Misses uint64 `json:"misses"`
+
+	// Tokens that hits saved, as recorded in the entries' usage.
+	PromptTokensSaved     uint64 `json:"prompt_tokens_saved"`
+	CompletionTokensSaved uint64 `json:"completion_tokens_saved"`
 }
 
 // HitRate returns the cache hit rate as a percentage.
@@ -47,41 +63,104 @@ func (s Stats) HitRate() float64 {
 	return float64(s.Hits) / float64(total) * 100
 }
 
+// NeverExpire is a TTL that keeps an entry until it is evicted or deleted.
+const NeverExpire time.Duration = -1
+
+// gcInterval is how often the value log is garbage collected and the size
+// limits are checked against the entries actually stored.
+const gcInterval = 5 * time.Minute
+
 // Cache wraps BadgerDB for LLM response caching.
 type Cache struct {
-	db     *badger.DB
-	hits   atomic.Uint64
-	misses atomic.Uint64
+	db *badger.DB
+	l1 l1 // recently used entries, held in memory
+
+	statsMu sync.Mutex
+	session Stats            // since the cache was opened
+	pending map[Labels]Stats // not yet added to the persisted statistics
+	flushMu sync.Mutex       // serializes saving and resetting the statistics
+
+	// Limits on what is kept; zero means no limit.
+	maxEntries int64
+	maxSize    int64
+
+	// Approximate totals of the stored entries, corrected on each eviction
+	// pass; expired entries are counted until then.
+	entries atomic.Int64
+	size    atomic.Int64
+
+	evict   chan struct{} // requests an eviction pass
+	done    chan struct{} // closed by Close
+	stopped chan struct{} // closed when the background goroutine exits
+}
+
+// Option configures a Cache.
+type Option func(*Cache)
+
+// WithMaxEntries limits the cache to n entries, evicting the least recently
+// used beyond that. Zero means no limit.
+func WithMaxEntries(n int) Option {
+	return func(c *Cache) { c.maxEntries = int64(n) }
+}
+
+// WithMaxSize limits the stored keys and values to about n bytes, evicting
+// the least recently used entries beyond that. The files on disk shrink as
+// the value log is garbage collected. Zero means no limit.
+func WithMaxSize(n int64) Option {
+	return func(c *Cache) { c.maxSize = n }
 }
 
 // New creates a new cache at the given path.
-func New(path string) (*Cache, error) {
-	opts := badger.DefaultOptions(path)
-	opts.Logger = nil // Disable BadgerDB internal logging
+func New(path string, opts ...Option) (*Cache, error) {
+	bopts := badger.DefaultOptions(path)
+	bopts.Logger = nil // Disable BadgerDB internal logging
 
-	db, err := badger.Open(opts)
+	db, err := badger.Open(bopts)
 	if err != nil {
 		return nil, fmt.Errorf("open badger: %w", err)
 	}
 
-	c := &Cache{db: db}
+	c := &Cache{
+		db:      db,
+		l1:      newL1(DefaultL1Size),
+		pending: make(map[Labels]Stats),
+		evict:   make(chan struct{}, 1),
+		done:    make(chan struct{}),
+		stopped: make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
 
-	// Start background GC goroutine
-	go c.runGC()
+	// Start background GC and eviction goroutine
+	go c.run()
 
 	return c, nil
 }
 
-// runGC periodically runs BadgerDB garbage collection.
-func (c *Cache) runGC() {
-	ticker := time.NewTicker(5 * time.Minute)
+// run counts the stored entries, evicting any over the limits, then
+// periodically runs BadgerDB garbage collection, saves statistics and
+// evicts entries when asked to, until the cache is closed.
+func (c *Cache) run() {
+	defer close(c.stopped)
+	ticker := time.NewTicker(gcInterval)
 	defer ticker.Stop()
+	statsTicker := time.NewTicker(statsFlushInterval)
+	defer statsTicker.Stop()
 
-	for range ticker.C {
-		if c.db.IsClosed() {
+	_ = c.evictLRU()
+	for {
+		select {
+		case <-c.done:
 			return
+		case <-c.evict:
+			_ = c.evictLRU()
+		case <-statsTicker.C:
+			_ = c.flushStats()
+		case <-ticker.C:
+			_ = c.evictLRU()
+			_ = c.db.RunValueLogGC(0.5)
 		}
-		_ = c.db.RunValueLogGC(0.5)
 	}
 }
 
@@ -90,9 +169,14 @@ var whitespaceRe = regexp.MustCompile(`\s+`)
 
 // GenerateKey creates a cache key from translation parameters.
 // The text is normalized before hashing to improve cache hit rate.
-func GenerateKey(provider, model, sourceLang, targetLang, text string) string {
+// Extra parts, such as the prompt template in use, further distinguish
+// translations of the same text; without them the key is unchanged.
+func GenerateKey(provider, model, sourceLang, targetLang, text string, extra ...string) string {
 	normalized := normalizeText(text)
 	data := fmt.Sprintf("%s|%s|%s|%s|%s", provider, model, sourceLang, targetLang, normalized)
+	for _, e := range extra {
+		data += "|" + e
+	}
 	hash := sha256.Sum256([]byte(data))
 	return hex.EncodeToString(hash[:])
 }
@@ -122,57 +206,102 @@ func normalizeText(s string) string {
 // Get retrieves an entry from the cache.
 // Returns nil and false if not found.
 func (c *Cache) Get(key string) (*Entry, bool) {
-	var entry Entry
+	return c.Lookup(key, Labels{})
+}
+
+// Lookup is like Get, recording the lookup in the statistics under l.
+func (c *Cache) Lookup(key string, l Labels) (*Entry, bool) {
+	if it, ok := c.l1.get(key); ok {
+		c.hit(l, it)
+		return &it.entry, true
+	}
 
+	seen := c.l1.snapshot()
+	it := l1Item{key: key}
 	err := c.db.View(func(txn *badger.Txn) error {
 		item, err := txn.Get([]byte(key))
 		if err != nil {
 			return err
 		}
+		it.expiresAt = item.ExpiresAt()
 
-		return item.Value(func(val []byte) error {
-			return json.Unmarshal(val, &entry)
+		err = item.Value(func(val []byte) error {
+			return json.Unmarshal(val, &it.entry)
 		})
+		if err != nil {
+			return err
+		}
+		it.accessed = lastAccess(txn, key)
+		return nil
 	})
 
 	if err != nil {
-		c.misses.Add(1)
+		c.record(l, Stats{Misses: 1})
 		return nil, false
 	}
 
-	c.hits.Add(1)
-	return &entry, true
+	c.l1.fill(seen, it)
+	c.hit(l, it)
+	return &it.entry, true
 }
 
-// Set stores an entry in the cache with the given TTL.
-func (c *Cache) Set(key string, entry *Entry, ttl time.Duration) error {
-	if ttl == 0 {
-		ttl = DefaultTTL
+// hit records a lookup that found it, and the access if the recorded one
+// is stale.
+func (c *Cache) hit(l Labels, it l1Item) {
+	c.record(l, Stats{
+		Hits:                  1,
+		PromptTokensSaved:     uint64(max(it.entry.Usage.PromptTokens, 0)),
+		CompletionTokensSaved: uint64(max(it.entry.Usage.CompletionTokens, 0)),
+	})
+	if time.Since(it.accessed) >= accessResolution {
+		if err := c.touch(it.key, it.expiresAt); err == nil {
+			c.l1.markAccessed(it.key, time.Now())
+		}
 	}
+}
 
+// Set stores an entry in the cache with the given TTL. A TTL of 0 uses
+// DefaultTTL; NeverExpire keeps the entry until it is evicted or deleted.
+func (c *Cache) Set(key string, entry *Entry, ttl time.Duration) error {
+	if !validKey(key) {
+		return fmt.Errorf("invalid cache key %q", key)
+	}
 	data, err := json.Marshal(entry)
 	if err != nil {
 		return fmt.Errorf("marshal entry: %w", err)
 	}
 
-	return c.db.Update(func(txn *badger.Txn) error {
-		e := badger.NewEntry([]byte(key), data).WithTTL(ttl)
-		return txn.SetEntry(e)
+	seen := c.l1.snapshot()
+	expiresAt := expiry(ttl)
+	var d delta
+	err = c.db.Update(func(txn *badger.Txn) error {
+		var err error
+		d, err = setEntry(txn, key, data, expiresAt)
+		return err
 	})
+	if err != nil {
+		return err
+	}
+	c.apply(d)
+	c.l1.fill(seen, l1Item{key: key, entry: *entry, expiresAt: expiresAt, accessed: time.Now()})
+	return nil
 }
 
-// Stats returns current cache statistics.
+// Stats returns cache statistics since the cache was opened. Report
+// returns those persisted across restarts.
 func (c *Cache) Stats() Stats {
-	return Stats{
-		Hits:   c.hits.Load(),
-		Misses: c.misses.Load(),
-	}
+	c.statsMu.Lock()
+	defer c.statsMu.Unlock()
+	return c.session
 }
 
-// Close closes the cache database.
+// Close saves the statistics and closes the cache database.
 func (c *Cache) Close() error {
 	if c.db != nil {
-		return c.db.Close()
+		close(c.done)
+		<-c.stopped
+		err := c.flushStats()
+		return errors.Join(err, c.db.Close())
 	}
 	return nil
 }
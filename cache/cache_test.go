@@ -115,6 +115,15 @@ func TestGenerateKey(t *testing.T) {
 	if key1 == key4 {
 		t.Error("different model produced same key")
 	}
+
+	// Extra parts should distinguish keys
+	key5 := GenerateKey("openai", "gpt-4", "en", "zh", "Hello", "formal")
+	if key1 == key5 {
+		t.Error("extra part did not change key")
+	}
+	if key5 != GenerateKey("openai", "gpt-4", "en", "zh", "Hello", "formal") {
+		t.Error("same extra parts produced different keys")
+	}
 }
 
 func TestGenerateKeyNormalization(t *testing.T) {
@@ -0,0 +1,319 @@
+package cache
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/dgraph-io/badger/v4"
+)
+
+// Keys starting with metaPrefix hold the cache's own bookkeeping rather
+// than entries. Entry keys are hex digests, so they never collide.
+const metaPrefix = "!"
+
+// accessPrefix starts the key holding an entry's last access time, which
+// expires with the entry.
+const accessPrefix = metaPrefix + "atime/"
+
+// accessResolution is how stale a recorded access time may get before a
+// hit rewrites it, so that repeated hits don't each cost a write.
+const accessResolution = time.Minute
+
+// evictTarget is the fraction of each limit an eviction pass frees the
+// cache down to, so that passes aren't needed on every write.
+const evictTarget = 0.9
+
+func accessKey(key string) []byte {
+	return []byte(accessPrefix + key)
+}
+
+func isMeta(key []byte) bool {
+	return bytes.HasPrefix(key, []byte(metaPrefix))
+}
+
+// lastAccess returns when the entry under key was last read or written, or
+// the zero time if that isn't recorded.
+func lastAccess(txn *badger.Txn, key string) time.Time {
+	item, err := txn.Get(accessKey(key))
+	if err != nil {
+		return time.Time{}
+	}
+	var t time.Time
+	_ = item.Value(func(val []byte) error {
+		t = decodeTime(val)
+		return nil
+	})
+	return t
+}
+
+func encodeTime(t time.Time) []byte {
+	return binary.BigEndian.AppendUint64(nil, uint64(t.UnixNano()))
+}
+
+func decodeTime(b []byte) time.Time {
+	if len(b) != 8 {
+		return time.Time{}
+	}
+	return time.Unix(0, int64(binary.BigEndian.Uint64(b)))
+}
+
+// delta is the change a write makes to the cache's totals.
+type delta struct {
+	entries int64
+	size    int64
+}
+
+// expiry returns when an entry written now with ttl expires, in Unix
+// seconds as Badger records it, or 0 for never. A TTL of 0 uses DefaultTTL.
+func expiry(ttl time.Duration) uint64 {
+	if ttl == 0 {
+		ttl = DefaultTTL
+	}
+	if ttl < 0 {
+		return 0
+	}
+	return uint64(time.Now().Add(ttl).Unix())
+}
+
+// setEntry stores data under key to expire at expiresAt, recording the
+// access, and returns the change in totals.
+func setEntry(txn *badger.Txn, key string, data []byte, expiresAt uint64) (delta, error) {
+	var d delta
+	switch item, err := txn.Get([]byte(key)); {
+	case errors.Is(err, badger.ErrKeyNotFound):
+		d.entries = 1
+	case err != nil:
+		return d, err
+	default:
+		d.size = -entrySize(item)
+	}
+	d.size += int64(len(key) + len(data))
+
+	e := badger.NewEntry([]byte(key), data)
+	e.ExpiresAt = expiresAt
+	access := badger.NewEntry(accessKey(key), encodeTime(time.Now()))
+	access.ExpiresAt = expiresAt
+	if err := txn.SetEntry(e); err != nil {
+		return d, err
+	}
+	return d, txn.SetEntry(access)
+}
+
+// deleteEntry removes key and its access time, returning the change in
+// totals.
+func deleteEntry(txn *badger.Txn, key string) (delta, error) {
+	item, err := txn.Get([]byte(key))
+	if errors.Is(err, badger.ErrKeyNotFound) {
+		return delta{}, txn.Delete(accessKey(key))
+	}
+	if err != nil {
+		return delta{}, err
+	}
+	d := delta{entries: -1, size: -entrySize(item)}
+	if err := txn.Delete([]byte(key)); err != nil {
+		return d, err
+	}
+	return d, txn.Delete(accessKey(key))
+}
+
+func entrySize(item *badger.Item) int64 {
+	return int64(item.KeySize()) + item.ValueSize()
+}
+
+// apply adds d to the totals, asking for an eviction pass if they are now
+// over a limit.
+func (c *Cache) apply(d delta) {
+	entries := c.entries.Add(d.entries)
+	size := c.size.Add(d.size)
+	if c.maxEntries > 0 && entries > c.maxEntries || c.maxSize > 0 && size > c.maxSize {
+		select {
+		case c.evict <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// touch records an access to the entry under key, which expires at
+// expiresAt (Unix seconds; 0 for never).
+func (c *Cache) touch(key string, expiresAt uint64) error {
+	return c.db.Update(func(txn *badger.Txn) error {
+		e := badger.NewEntry(accessKey(key), encodeTime(time.Now()))
+		e.ExpiresAt = expiresAt
+		return txn.SetEntry(e)
+	})
+}
+
+// candidate is an entry considered for eviction, as found by a scan.
+type candidate struct {
+	key      []byte
+	size     int64
+	accessed time.Time
+}
+
+// evictLRU recounts the stored entries and, if they are over a limit,
+// deletes the least recently used down to evictTarget of it. Access times
+// left behind by expired entries are deleted along the way.
+func (c *Cache) evictLRU() error {
+	var entries []candidate
+	accessed := make(map[string]time.Time)
+
+	err := c.db.View(func(txn *badger.Txn) error {
+		opts := badger.DefaultIteratorOptions
+		opts.PrefetchValues = false
+		it := txn.NewIterator(opts)
+		defer it.Close()
+
+		for it.Rewind(); it.Valid(); it.Next() {
+			item := it.Item()
+			key := item.KeyCopy(nil)
+			switch {
+			case bytes.HasPrefix(key, []byte(accessPrefix)):
+				err := item.Value(func(val []byte) error {
+					accessed[string(key[len(accessPrefix):])] = decodeTime(val)
+					return nil
+				})
+				if err != nil {
+					return err
+				}
+			case !isMeta(key):
+				entries = append(entries, candidate{key: key, size: entrySize(item)})
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	var total int64
+	for i := range entries {
+		key := string(entries[i].key)
+		entries[i].accessed = accessed[key] // zero, so first to go, if never recorded
+		delete(accessed, key)
+		total += entries[i].size
+	}
+	count := int64(len(entries))
+
+	if err := c.deleteOrphans(slices.Collect(maps.Keys(accessed))); err != nil {
+		return err
+	}
+
+	if c.maxEntries > 0 && count > c.maxEntries || c.maxSize > 0 && total > c.maxSize {
+		slices.SortFunc(entries, func(a, b candidate) int {
+			return a.accessed.Compare(b.accessed)
+		})
+		if count, total, err = c.evictOldest(entries, count, total); err != nil {
+			return err
+		}
+	}
+
+	// Writes since the scan are lost from the totals until the next pass.
+	c.entries.Store(count)
+	c.size.Store(total)
+	return nil
+}
+
+// evictOldest deletes entries, least recently used first, until count and
+// total are within evictTarget of the limits, and returns what is left.
+// Each batch re-reads the access times in the transaction that deletes it,
+// keeping entries read or rewritten since the scan that found them; a Set
+// or touch committed meanwhile makes the batch conflict, and it is tried
+// again.
+func (c *Cache) evictOldest(entries []candidate, count, total int64) (int64, int64, error) {
+	maxEntries := int64(float64(c.maxEntries) * evictTarget)
+	maxSize := int64(float64(c.maxSize) * evictTarget)
+	over := func(count, total int64) bool {
+		return c.maxEntries > 0 && count > maxEntries || c.maxSize > 0 && total > maxSize
+	}
+
+	conflicts := 0
+	for len(entries) > 0 && over(count, total) {
+		batch := entries[:min(evictBatch, len(entries))]
+		var evicted []string
+		var n, t int64
+		err := c.db.Update(func(txn *badger.Txn) error {
+			evicted, n, t = nil, count, total
+			for _, e := range batch {
+				if !over(n, t) {
+					break
+				}
+				key := string(e.key)
+				if _, err := txn.Get(e.key); errors.Is(err, badger.ErrKeyNotFound) {
+					n-- // expired or deleted since the scan
+					t -= e.size
+					continue
+				} else if err != nil {
+					return err
+				}
+				if !lastAccess(txn, key).Equal(e.accessed) {
+					continue // used since the scan
+				}
+				if err := txn.Delete(e.key); err != nil {
+					return err
+				}
+				if err := txn.Delete(accessKey(key)); err != nil {
+					return err
+				}
+				evicted = append(evicted, key)
+				n--
+				t -= e.size
+			}
+			return nil
+		})
+		if errors.Is(err, badger.ErrConflict) {
+			if conflicts++; conflicts > maxEvictConflicts {
+				break // leave the rest to the next pass
+			}
+			continue
+		}
+		if err != nil {
+			return count, total, err
+		}
+		c.l1.remove(evicted...)
+		count, total = n, t
+		entries = entries[len(batch):]
+	}
+	return count, total, nil
+}
+
+// evictBatch is how many entries an eviction transaction considers, well
+// within Badger's limit on the size of a transaction.
+const evictBatch = 256
+
+// maxEvictConflicts is how many times an eviction pass retries batches
+// that conflicted with other writes before leaving the rest to the next
+// pass.
+const maxEvictConflicts = 3
+
+// deleteOrphans deletes the access times under keys whose entries are
+// gone, unless the entry was written again since.
+func (c *Cache) deleteOrphans(keys []string) error {
+	for batch := range slices.Chunk(keys, evictBatch) {
+		err := c.db.Update(func(txn *badger.Txn) error {
+			for _, key := range batch {
+				if _, err := txn.Get([]byte(key)); !errors.Is(err, badger.ErrKeyNotFound) {
+					continue
+				}
+				if err := txn.Delete(accessKey(key)); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		// A conflicting write left them for the next pass.
+		if err != nil && !errors.Is(err, badger.ErrConflict) {
+			return err
+		}
+	}
+	return nil
+}
+
+// validKey reports whether key may name an entry.
+func validKey(key string) bool {
+	return key != "" && !strings.HasPrefix(key, metaPrefix)
+}
@@ -0,0 +1,203 @@
+package cache
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/dgraph-io/badger/v4"
+)
+
+// setAccess records that the entry under key was last used at t.
+func setAccess(t *testing.T, c *Cache, key string, at time.Time) {
+	t.Helper()
+	err := c.db.Update(func(txn *badger.Txn) error {
+		return txn.Set(accessKey(key), encodeTime(at))
+	})
+	if err != nil {
+		t.Fatalf("set access time: %v", err)
+	}
+	c.l1.markAccessed(key, at)
+}
+
+func TestEvictLRU(t *testing.T) {
+	tests := []struct {
+		name string
+		opt  Option
+		want []string // entries left, by source text
+	}{
+		// 5 entries over a limit of 4 are cut to 90% of it: 3.
+		{"max entries", WithMaxEntries(4), []string{"2", "3", "4"}},
+		{"no limit", WithMaxEntries(0), []string{"0", "1", "2", "3", "4"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "cache")
+			c := openTestCache(t, path)
+			// Written in reverse, so that recency of use rather than of
+			// writing decides what goes.
+			base := time.Now().Add(-time.Hour)
+			for i := 4; i >= 0; i-- {
+				text := fmt.Sprint(i)
+				key := GenerateKey("p", "m", "en", "zh", text)
+				if err := c.Set(key, &Entry{SourceText: text, Text: text}, DefaultTTL); err != nil {
+					t.Fatalf("set: %v", err)
+				}
+				setAccess(t, c, key, base.Add(time.Duration(i)*time.Minute))
+			}
+			if err := c.Close(); err != nil {
+				t.Fatalf("close: %v", err)
+			}
+
+			// Opening with the limit evicts in the background; evict here
+			// too so that it has happened before checking.
+			c = openTestCache(t, path, tt.opt)
+			defer c.Close()
+			if err := c.evictLRU(); err != nil {
+				t.Fatalf("evict: %v", err)
+			}
+
+			recs, err := c.Search("", 0)
+			if err != nil {
+				t.Fatalf("search: %v", err)
+			}
+			var left []string
+			for _, r := range recs {
+				left = append(left, r.SourceText)
+			}
+			slices.Sort(left)
+			if !slices.Equal(left, tt.want) {
+				t.Errorf("left %q, want %q", left, tt.want)
+			}
+			if n := c.entries.Load(); n != int64(len(tt.want)) {
+				t.Errorf("entry count = %d, want %d", n, len(tt.want))
+			}
+		})
+	}
+}
+
+func TestEvictBySize(t *testing.T) {
+	c := newTestCache(t, WithMaxSize(1000))
+
+	for i := range 10 {
+		key := GenerateKey("p", "m", "en", "zh", fmt.Sprint(i))
+		if err := c.Set(key, &Entry{Text: fmt.Sprintf("%0200d", i)}, DefaultTTL); err != nil {
+			t.Fatalf("set: %v", err)
+		}
+	}
+	if err := c.evictLRU(); err != nil {
+		t.Fatalf("evict: %v", err)
+	}
+	if size := c.size.Load(); size > 900 || size == 0 {
+		t.Errorf("size after eviction = %d, want at most 900", size)
+	}
+}
+
+func TestGetRecordsAccess(t *testing.T) {
+	c := newTestCache(t)
+
+	key := GenerateKey("p", "m", "en", "zh", "hello")
+	if err := c.Set(key, &Entry{Text: "你好"}, DefaultTTL); err != nil {
+		t.Fatalf("set: %v", err)
+	}
+	old := time.Now().Add(-time.Hour)
+	setAccess(t, c, key, old)
+
+	if _, ok := c.Get(key); !ok {
+		t.Fatal("entry not found")
+	}
+	var accessed time.Time
+	err := c.db.View(func(txn *badger.Txn) error {
+		accessed = lastAccess(txn, key)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("view: %v", err)
+	}
+	if !accessed.After(old) {
+		t.Errorf("access time not updated on hit: %v", accessed)
+	}
+}
+
+func TestTTL(t *testing.T) {
+	c := newTestCache(t)
+
+	tests := []struct {
+		name   string
+		ttl    time.Duration
+		expire bool
+	}{
+		{"default", 0, true},
+		{"custom", time.Hour, true},
+		{"never", NeverExpire, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key := GenerateKey("p", "m", "en", "zh", tt.name)
+			if err := c.Set(key, &Entry{Text: tt.name}, tt.ttl); err != nil {
+				t.Fatalf("set: %v", err)
+			}
+			err := c.db.View(func(txn *badger.Txn) error {
+				item, err := txn.Get([]byte(key))
+				if err != nil {
+					return err
+				}
+				access, err := txn.Get(accessKey(key))
+				if err != nil {
+					return fmt.Errorf("access time: %w", err)
+				}
+				if expires := item.ExpiresAt() != 0; expires != tt.expire {
+					t.Errorf("entry expires = %v, want %v", expires, tt.expire)
+				}
+				if access.ExpiresAt() != item.ExpiresAt() {
+					t.Errorf("access time expires at %d, entry at %d", access.ExpiresAt(), item.ExpiresAt())
+				}
+				return nil
+			})
+			if err != nil {
+				t.Fatalf("get: %v", err)
+			}
+		})
+	}
+
+	if err := c.Set(accessPrefix+"x", &Entry{}, 0); err == nil {
+		t.Error("expected error for a bookkeeping key")
+	}
+}
+
+func TestEvictKeepsEntriesUsedSinceScan(t *testing.T) {
+	c := newTestCache(t, WithMaxEntries(2)) // cut to 90% when over: 1
+
+	base := time.Now().Add(-time.Hour)
+	var scanned []candidate
+	for i, text := range []string{"used", "idle"} {
+		key := GenerateKey("p", "m", "en", "zh", text)
+		if err := c.Set(key, &Entry{Text: text}, DefaultTTL); err != nil {
+			t.Fatalf("set: %v", err)
+		}
+		at := base.Add(time.Duration(i) * time.Minute)
+		setAccess(t, c, key, at)
+		scanned = append(scanned, candidate{key: []byte(key), accessed: at})
+	}
+
+	// The least recently used entry is read after the scan, so the other
+	// goes instead.
+	used := string(scanned[0].key)
+	setAccess(t, c, used, time.Now())
+
+	count, _, err := c.evictOldest(scanned, 2, 0)
+	if err != nil {
+		t.Fatalf("evict: %v", err)
+	}
+	if count != 1 {
+		t.Errorf("count = %d, want 1", count)
+	}
+	if _, ok := c.Get(used); !ok {
+		t.Error("entry used since the scan was evicted")
+	}
+	if _, ok := c.Get(string(scanned[1].key)); ok {
+		t.Error("idle entry was kept")
+	}
+}
@@ -0,0 +1,161 @@
+package cache
+
+import (
+	"container/list"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// DefaultL1Size is how many entries the in-memory tier holds unless
+// WithL1Size says otherwise.
+const DefaultL1Size = 1024
+
+// WithL1Size keeps up to n recently used entries in memory in front of the
+// database, so that repeated lookups skip a read transaction and decoding.
+// Zero disables the in-memory tier.
+func WithL1Size(n int) Option {
+	return func(c *Cache) { c.l1.size = max(n, 0) }
+}
+
+// L1Stats counts lookups of the in-memory tier since the cache was opened
+// or its statistics were reset. Misses fall through to the database.
+type L1Stats struct {
+	Hits    uint64 `json:"hits"`
+	Misses  uint64 `json:"misses"`
+	Entries int    `json:"entries"`
+}
+
+// l1 holds the most recently used entries in memory. Writes go to the
+// database first and then here; each change bumps version, so that a fill
+// read from the database before a change is dropped instead of going
+// stale.
+type l1 struct {
+	mu      sync.Mutex
+	size    int
+	order   *list.List // of *l1Item, most recently used first
+	items   map[string]*list.Element
+	version uint64
+
+	hits   atomic.Uint64
+	misses atomic.Uint64
+}
+
+type l1Item struct {
+	key       string
+	entry     Entry
+	expiresAt uint64    // Unix seconds, as in Badger; 0 for never
+	accessed  time.Time // as last recorded in the database
+}
+
+func newL1(size int) l1 {
+	return l1{size: size, order: list.New(), items: make(map[string]*list.Element)}
+}
+
+// get returns the item under key, if held and not expired.
+func (m *l1) get(key string) (l1Item, bool) {
+	if m.size == 0 {
+		return l1Item{}, false
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	el, ok := m.items[key]
+	if ok {
+		it := el.Value.(*l1Item)
+		if it.expiresAt == 0 || it.expiresAt > uint64(time.Now().Unix()) {
+			m.order.MoveToFront(el)
+			m.hits.Add(1)
+			return *it, true
+		}
+		m.order.Remove(el)
+		delete(m.items, key)
+	}
+	m.misses.Add(1)
+	return l1Item{}, false
+}
+
+// snapshot returns the version to pass to fill after reading the
+// database.
+func (m *l1) snapshot() uint64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.version
+}
+
+// fill holds it, unless something changed since snapshot returned seen, in
+// which case it may be out of date and is dropped.
+func (m *l1) fill(seen uint64, it l1Item) {
+	if m.size == 0 {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.version != seen {
+		m.removeLocked(it.key)
+		return
+	}
+	m.version++
+	if el, ok := m.items[it.key]; ok {
+		el.Value = &it
+		m.order.MoveToFront(el)
+		return
+	}
+	m.items[it.key] = m.order.PushFront(&it)
+	for m.order.Len() > m.size {
+		oldest := m.order.Back()
+		m.order.Remove(oldest)
+		delete(m.items, oldest.Value.(*l1Item).key)
+	}
+}
+
+// remove drops the items under keys, as their entries have changed or
+// gone.
+func (m *l1) remove(keys ...string) {
+	if m.size == 0 {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, key := range keys {
+		m.removeLocked(key)
+	}
+}
+
+func (m *l1) removeLocked(key string) {
+	m.version++
+	if el, ok := m.items[key]; ok {
+		m.order.Remove(el)
+		delete(m.items, key)
+	}
+}
+
+// markAccessed notes that the access to the item under key was recorded
+// in the database at t.
+func (m *l1) markAccessed(key string, t time.Time) {
+	if m.size == 0 {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if el, ok := m.items[key]; ok {
+		el.Value.(*l1Item).accessed = t
+	}
+}
+
+func (m *l1) stats() L1Stats {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return L1Stats{Hits: m.hits.Load(), Misses: m.misses.Load(), Entries: m.order.Len()}
+}
+
+func (m *l1) resetStats() {
+	m.hits.Store(0)
+	m.misses.Store(0)
+}
+
+// L1Stats returns the statistics of the in-memory tier.
+func (c *Cache) L1Stats() L1Stats {
+	return c.l1.stats()
+}
@@ -0,0 +1,199 @@
+package cache
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestL1(t *testing.T) {
+	key := GenerateKey("p", "m", "en", "zh", "hello")
+	other := GenerateKey("p", "m", "en", "zh", "other")
+
+	tests := []struct {
+		name   string
+		opts   []Option
+		change func(t *testing.T, c *Cache) // made after the entry is cached
+		want   string                       // text found afterwards; "" for none
+		l1     L1Stats                      // after the lookup that follows
+	}{
+		{
+			name:   "hit",
+			change: func(t *testing.T, c *Cache) {},
+			want:   "你好",
+			l1:     L1Stats{Hits: 1, Entries: 1},
+		},
+		{
+			name: "set",
+			change: func(t *testing.T, c *Cache) {
+				if err := c.Set(key, &Entry{Text: "您好"}, DefaultTTL); err != nil {
+					t.Fatalf("set: %v", err)
+				}
+			},
+			want: "您好",
+			l1:   L1Stats{Hits: 1, Entries: 1},
+		},
+		{
+			name: "delete",
+			change: func(t *testing.T, c *Cache) {
+				if err := c.Delete(key); err != nil {
+					t.Fatalf("delete: %v", err)
+				}
+			},
+			l1: L1Stats{Misses: 1},
+		},
+		{
+			name: "import",
+			change: func(t *testing.T, c *Cache) {
+				data := fmt.Sprintf(`{"key":%q,"text":"您好","created_at":%q}`+"\n",
+					key, time.Now().Add(time.Hour).Format(time.RFC3339))
+				if _, err := c.Import(bytes.NewBufferString(data), FormatJSONL, Filter{}, KeepNewest, DefaultTTL); err != nil {
+					t.Fatalf("import: %v", err)
+				}
+			},
+			want: "您好",
+			l1:   L1Stats{Misses: 1, Entries: 1},
+		},
+		{
+			name: "evict",
+			// Over the limit, entries are cut to 90% of it: both go.
+			opts: []Option{WithMaxEntries(1)},
+			change: func(t *testing.T, c *Cache) {
+				if err := c.Set(other, &Entry{Text: "其他"}, DefaultTTL); err != nil {
+					t.Fatalf("set: %v", err)
+				}
+				// The write asked for eviction in the background; make sure
+				// it has happened.
+				if err := c.evictLRU(); err != nil {
+					t.Fatalf("evict: %v", err)
+				}
+			},
+			l1: L1Stats{Misses: 1},
+		},
+		{
+			name: "expired",
+			change: func(t *testing.T, c *Cache) {
+				// Badger expires entries to the second.
+				if err := c.Set(key, &Entry{Text: "您好"}, time.Second); err != nil {
+					t.Fatalf("set: %v", err)
+				}
+				time.Sleep(2 * time.Second)
+			},
+			l1: L1Stats{Misses: 1},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newTestCache(t, tt.opts...)
+
+			if err := c.Set(key, &Entry{Text: "你好"}, DefaultTTL); err != nil {
+				t.Fatalf("set: %v", err)
+			}
+			tt.change(t, c)
+			if err := c.ResetStats(); err != nil {
+				t.Fatalf("reset stats: %v", err)
+			}
+
+			got := ""
+			if e, ok := c.Get(key); ok {
+				got = e.Text
+			}
+			if got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+			if s := c.L1Stats(); s != tt.l1 {
+				t.Errorf("l1 stats = %+v, want %+v", s, tt.l1)
+			}
+		})
+	}
+}
+
+func TestL1Bounded(t *testing.T) {
+	c := newTestCache(t, WithL1Size(2))
+
+	keys := make([]string, 3)
+	for i := range keys {
+		keys[i] = GenerateKey("p", "m", "en", "zh", fmt.Sprint(i))
+		if err := c.Set(keys[i], &Entry{Text: fmt.Sprint(i)}, DefaultTTL); err != nil {
+			t.Fatalf("set: %v", err)
+		}
+	}
+
+	// The first entry was pushed out of memory, but is still stored.
+	if e, ok := c.Get(keys[0]); !ok || e.Text != "0" {
+		t.Fatalf("get = %v, %v", e, ok)
+	}
+	want := L1Stats{Misses: 1, Entries: 2}
+	if s := c.L1Stats(); s != want {
+		t.Errorf("l1 stats = %+v, want %+v", s, want)
+	}
+
+	// Reading it back in pushed out the least recently used: the second.
+	if _, ok := c.l1.items[keys[1]]; ok {
+		t.Error("least recently used entry still held")
+	}
+	if _, ok := c.Get(keys[2]); !ok {
+		t.Fatal("entry not found")
+	}
+	if s := c.L1Stats(); s.Hits != 1 {
+		t.Errorf("l1 hits = %d, want 1", s.Hits)
+	}
+}
+
+func TestL1Disabled(t *testing.T) {
+	c := newTestCache(t, WithL1Size(0))
+
+	key := GenerateKey("p", "m", "en", "zh", "hello")
+	if err := c.Set(key, &Entry{Text: "你好"}, DefaultTTL); err != nil {
+		t.Fatalf("set: %v", err)
+	}
+	if _, ok := c.Get(key); !ok {
+		t.Fatal("entry not found")
+	}
+	if s := c.L1Stats(); s != (L1Stats{}) {
+		t.Errorf("l1 stats = %+v, want none", s)
+	}
+}
+
+// BenchmarkLookup compares repeated lookups of the same entries with and
+// without the in-memory tier.
+func BenchmarkLookup(b *testing.B) {
+	for _, bb := range []struct {
+		name string
+		size int
+	}{
+		{"l1", DefaultL1Size},
+		{"badger", 0},
+	} {
+		b.Run(bb.name, func(b *testing.B) {
+			c := newTestCache(b, WithL1Size(bb.size))
+
+			keys := make([]string, 100)
+			for i := range keys {
+				text := fmt.Sprintf("The quick brown fox jumps over the lazy dog, %d times.", i)
+				keys[i] = GenerateKey("p", "m", "en", "zh", text)
+				entry := &Entry{
+					Text:       fmt.Sprintf("敏捷的棕色狐狸跳过了懒狗 %d 次。", i),
+					Usage:      Usage{PromptTokens: 40, CompletionTokens: 20, TotalTokens: 60},
+					CreatedAt:  time.Now(),
+					SourceText: text,
+					SourceLang: "en",
+					TargetLang: "zh",
+					Provider:   "p",
+					Model:      "m",
+				}
+				if err := c.Set(keys[i], entry, DefaultTTL); err != nil {
+					b.Fatalf("set: %v", err)
+				}
+			}
+
+			b.ReportAllocs()
+			for i := 0; b.Loop(); i++ {
+				if _, ok := c.Get(keys[i%len(keys)]); !ok {
+					b.Fatal("entry not found")
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,244 @@
+package cache
+
+import (
+	"cmp"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/dgraph-io/badger/v4"
+)
+
+// statsPrefix starts the keys holding persisted statistics, one per set of
+// labels.
+const statsPrefix = metaPrefix + "stats/"
+
+// statsSinceKey holds when the persisted statistics were started.
+const statsSinceKey = metaPrefix + "stats-since"
+
+// statsFlushInterval is how often recorded lookups are persisted; at most
+// this much is lost if the app exits without closing the cache.
+const statsFlushInterval = time.Minute
+
+// Labels break statistics down by what was looked up.
+type Labels struct {
+	Provider   string `json:"provider"`
+	Model      string `json:"model"`
+	SourceLang string `json:"source_lang"`
+	TargetLang string `json:"target_lang"`
+}
+
+// labelSep separates labels in statistics keys.
+const labelSep = "\x00"
+
+func (l Labels) key() []byte {
+	return []byte(statsPrefix + strings.Join([]string{l.Provider, l.Model, l.SourceLang, l.TargetLang}, labelSep))
+}
+
+func parseLabels(key []byte) (Labels, bool) {
+	parts := strings.Split(strings.TrimPrefix(string(key), statsPrefix), labelSep)
+	if len(parts) != 4 {
+		return Labels{}, false
+	}
+	return Labels{Provider: parts[0], Model: parts[1], SourceLang: parts[2], TargetLang: parts[3]}, true
+}
+
+func (s *Stats) add(o Stats) {
+	s.Hits += o.Hits
+	s.Misses += o.Misses
+	s.PromptTokensSaved += o.PromptTokensSaved
+	s.CompletionTokensSaved += o.CompletionTokensSaved
+}
+
+// GroupStats are the statistics of lookups with the same labels.
+type GroupStats struct {
+	Labels
+	Stats
+}
+
+// Report holds the statistics persisted since Since, in total and by
+// labels.
+type Report struct {
+	Since  time.Time    `json:"since"`
+	Total  Stats        `json:"total"`
+	Groups []GroupStats `json:"groups"` // most hits first
+}
+
+// record counts a lookup with the given labels.
+func (c *Cache) record(l Labels, s Stats) {
+	c.statsMu.Lock()
+	defer c.statsMu.Unlock()
+
+	c.session.add(s)
+	p := c.pending[l]
+	p.add(s)
+	c.pending[l] = p
+}
+
+// Report returns the statistics persisted across restarts, including
+// lookups not yet saved.
+func (c *Cache) Report() (Report, error) {
+	// Hold off saving, which would move lookups between the two sources.
+	c.flushMu.Lock()
+	defer c.flushMu.Unlock()
+
+	groups := make(map[Labels]Stats)
+	var since time.Time
+
+	err := c.db.View(func(txn *badger.Txn) error {
+		if item, err := txn.Get([]byte(statsSinceKey)); err == nil {
+			_ = item.Value(func(val []byte) error {
+				since = decodeTime(val)
+				return nil
+			})
+		} else if !errors.Is(err, badger.ErrKeyNotFound) {
+			return err
+		}
+
+		opts := badger.DefaultIteratorOptions
+		opts.Prefix = []byte(statsPrefix)
+		it := txn.NewIterator(opts)
+		defer it.Close()
+
+		for it.Rewind(); it.Valid(); it.Next() {
+			item := it.Item()
+			l, ok := parseLabels(item.Key())
+			if !ok {
+				continue
+			}
+			var s Stats
+			if err := item.Value(func(val []byte) error {
+				return json.Unmarshal(val, &s)
+			}); err != nil {
+				return fmt.Errorf("decode stats: %w", err)
+			}
+			groups[l] = s
+		}
+		return nil
+	})
+	if err != nil {
+		return Report{}, err
+	}
+
+	c.statsMu.Lock()
+	for l, p := range c.pending {
+		s := groups[l]
+		s.add(p)
+		groups[l] = s
+	}
+	c.statsMu.Unlock()
+
+	r := Report{Since: since}
+	for l, s := range groups {
+		r.Total.add(s)
+		r.Groups = append(r.Groups, GroupStats{Labels: l, Stats: s})
+	}
+	slices.SortFunc(r.Groups, func(a, b GroupStats) int {
+		return cmp.Or(
+			cmp.Compare(b.Hits, a.Hits),
+			strings.Compare(a.Provider, b.Provider),
+			strings.Compare(a.Model, b.Model),
+			strings.Compare(a.SourceLang, b.SourceLang),
+			strings.Compare(a.TargetLang, b.TargetLang),
+		)
+	})
+	return r, nil
+}
+
+// ResetStats discards the persisted statistics and those since the cache
+// was opened.
+func (c *Cache) ResetStats() error {
+	c.flushMu.Lock()
+	defer c.flushMu.Unlock()
+
+	err := c.db.Update(func(txn *badger.Txn) error {
+		opts := badger.DefaultIteratorOptions
+		opts.Prefix = []byte(statsPrefix)
+		opts.PrefetchValues = false
+		it := txn.NewIterator(opts)
+		defer it.Close()
+
+		for it.Rewind(); it.Valid(); it.Next() {
+			if err := txn.Delete(it.Item().KeyCopy(nil)); err != nil {
+				return err
+			}
+		}
+		return txn.Delete([]byte(statsSinceKey))
+	})
+	if err != nil {
+		return fmt.Errorf("reset stats: %w", err)
+	}
+
+	c.statsMu.Lock()
+	defer c.statsMu.Unlock()
+	c.session = Stats{}
+	clear(c.pending)
+	c.l1.resetStats()
+	return nil
+}
+
+// flushStats adds the recorded lookups to the persisted statistics.
+func (c *Cache) flushStats() error {
+	c.flushMu.Lock()
+	defer c.flushMu.Unlock()
+
+	c.statsMu.Lock()
+	pending := c.pending
+	c.pending = make(map[Labels]Stats)
+	c.statsMu.Unlock()
+
+	if len(pending) == 0 {
+		return nil
+	}
+
+	err := c.db.Update(func(txn *badger.Txn) error {
+		if _, err := txn.Get([]byte(statsSinceKey)); errors.Is(err, badger.ErrKeyNotFound) {
+			if err := txn.Set([]byte(statsSinceKey), encodeTime(time.Now())); err != nil {
+				return err
+			}
+		} else if err != nil {
+			return err
+		}
+
+		for l, p := range pending {
+			var s Stats
+			item, err := txn.Get(l.key())
+			switch {
+			case errors.Is(err, badger.ErrKeyNotFound):
+			case err != nil:
+				return err
+			default:
+				if err := item.Value(func(val []byte) error {
+					return json.Unmarshal(val, &s)
+				}); err != nil {
+					return err
+				}
+			}
+			s.add(p)
+
+			data, err := json.Marshal(s)
+			if err != nil {
+				return err
+			}
+			if err := txn.Set(l.key(), data); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		// Keep the lookups for the next attempt.
+		c.statsMu.Lock()
+		for l, p := range pending {
+			s := c.pending[l]
+			s.add(p)
+			c.pending[l] = s
+		}
+		c.statsMu.Unlock()
+		return fmt.Errorf("save stats: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,75 @@
+package cache
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestReportPersists(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache")
+	c := openTestCache(t, path)
+
+	openai := Labels{Provider: "openai", Model: "gpt-4", SourceLang: "en", TargetLang: "zh"}
+	claude := Labels{Provider: "claude", Model: "sonnet", SourceLang: "en", TargetLang: "ja"}
+	key := GenerateKey("openai", "gpt-4", "en", "zh", "hello")
+	if err := c.Set(key, &Entry{Text: "你好", Usage: Usage{PromptTokens: 20, CompletionTokens: 5}}, DefaultTTL); err != nil {
+		t.Fatalf("set: %v", err)
+	}
+
+	c.Lookup(key, openai)
+	c.Lookup(key, openai)
+	c.Lookup(GenerateKey("openai", "gpt-4", "en", "zh", "bye"), openai)
+	c.Lookup(GenerateKey("claude", "sonnet", "en", "ja", "hello"), claude)
+
+	// Half the lookups are saved before closing, the rest by Close.
+	if err := c.flushStats(); err != nil {
+		t.Fatalf("flush: %v", err)
+	}
+	c.Lookup(key, openai)
+	if err := c.Close(); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+
+	c = openTestCache(t, path)
+	defer c.Close()
+	c.Lookup(key, openai) // not yet saved, but reported
+
+	r, err := c.Report()
+	if err != nil {
+		t.Fatalf("report: %v", err)
+	}
+	if r.Since.IsZero() {
+		t.Error("report has no start time")
+	}
+	if len(r.Groups) != 2 || r.Groups[0].Labels != openai || r.Groups[1].Labels != claude {
+		t.Fatalf("groups = %+v, want openai then claude", r.Groups)
+	}
+
+	tests := []struct {
+		name string
+		got  Stats
+		want Stats
+	}{
+		{"total", r.Total, Stats{Hits: 4, Misses: 2, PromptTokensSaved: 80, CompletionTokensSaved: 20}},
+		{"openai", r.Groups[0].Stats, Stats{Hits: 4, Misses: 1, PromptTokensSaved: 80, CompletionTokensSaved: 20}},
+		{"claude", r.Groups[1].Stats, Stats{Misses: 1}},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("%s = %+v, want %+v", tt.name, tt.got, tt.want)
+		}
+	}
+	if s := c.Stats(); s.Hits != 1 || s.Misses != 0 {
+		t.Errorf("session stats = %+v, want the one hit since reopening", s)
+	}
+
+	if err := c.ResetStats(); err != nil {
+		t.Fatalf("reset: %v", err)
+	}
+	if r, err = c.Report(); err != nil {
+		t.Fatalf("report after reset: %v", err)
+	}
+	if r.Total != (Stats{}) || len(r.Groups) != 0 || !r.Since.IsZero() {
+		t.Errorf("report after reset = %+v", r)
+	}
+}
@@ -0,0 +1,457 @@
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dgraph-io/badger/v4"
+)
+
+// Import and export formats.
+const (
+	FormatJSONL = "jsonl" // one Record per line
+	FormatTMX   = "tmx"   // TMX 1.4, for CAT tools
+)
+
+// FormatOf returns the format implied by a file name's extension.
+func FormatOf(name string) (string, error) {
+	switch ext := strings.ToLower(filepath.Ext(name)); ext {
+	case ".jsonl":
+		return FormatJSONL, nil
+	case ".tmx":
+		return FormatTMX, nil
+	default:
+		return "", fmt.Errorf("unsupported cache file type: %s", ext)
+	}
+}
+
+// Filter selects entries by language pair, provider and creation time.
+// Zero fields match everything.
+type Filter struct {
+	SourceLang string    `json:"source_lang,omitempty"`
+	TargetLang string    `json:"target_lang,omitempty"`
+	Provider   string    `json:"provider,omitempty"`
+	Since      time.Time `json:"since,omitempty"` // inclusive
+	Until      time.Time `json:"until,omitempty"` // exclusive
+}
+
+// Match reports whether e passes the filter.
+func (f Filter) Match(e *Entry) bool {
+	switch {
+	case f.SourceLang != "" && !strings.EqualFold(f.SourceLang, e.SourceLang):
+		return false
+	case f.TargetLang != "" && !strings.EqualFold(f.TargetLang, e.TargetLang):
+		return false
+	case f.Provider != "" && f.Provider != e.Provider:
+		return false
+	case !f.Since.IsZero() && e.CreatedAt.Before(f.Since):
+		return false
+	case !f.Until.IsZero() && !e.CreatedAt.Before(f.Until):
+		return false
+	}
+	return true
+}
+
+// ConflictPolicy decides what an import does with an entry whose key is
+// already cached.
+type ConflictPolicy string
+
+const (
+	KeepNewest   ConflictPolicy = "newest"    // keep whichever was created last
+	KeepExisting ConflictPolicy = "existing"  // never replace cached entries
+	Overwrite    ConflictPolicy = "overwrite" // always replace cached entries
+)
+
+// ImportResult counts the entries an import read.
+type ImportResult struct {
+	Imported int `json:"imported"` // stored in the cache
+	Skipped  int `json:"skipped"`  // filtered out or kept out by the conflict policy
+}
+
+// Export writes the entries matching f in the given format and returns how
+// many were written. TMX has no place for entries without source text, such
+// as image translations, so they are left out of it.
+func (c *Cache) Export(w io.Writer, format string, f Filter) (int, error) {
+	var write func(Record) error
+	var finish func() error
+	switch format {
+	case FormatJSONL:
+		enc := json.NewEncoder(w)
+		write = func(rec Record) error { return enc.Encode(rec) }
+		finish = func() error { return nil }
+	case FormatTMX:
+		tw, err := newTMXWriter(w)
+		if err != nil {
+			return 0, err
+		}
+		write, finish = tw.write, tw.close
+	default:
+		return 0, fmt.Errorf("unsupported cache format: %s", format)
+	}
+
+	n := 0
+	var writeErr error
+	err := c.Iterate(func(rec Record) bool {
+		if !f.Match(&rec.Entry) || format == FormatTMX && rec.SourceText == "" {
+			return true
+		}
+		if writeErr = write(rec); writeErr != nil {
+			return false
+		}
+		n++
+		return true
+	})
+	if err == nil {
+		err = writeErr
+	}
+	if err == nil {
+		err = finish()
+	}
+	if err != nil {
+		return n, fmt.Errorf("export cache: %w", err)
+	}
+	return n, nil
+}
+
+// Import reads entries in the given format and stores those matching f,
+// resolving conflicts with existing entries by policy. Imported entries
+// live for ttl from now, as for Set.
+//
+// Entries without a key of this cache, such as TMX units from other tools,
+// are stored under a key derived from their provider, model, language pair
+// and source text. Such keys don't include the prompt, so lookups won't return these
+// entries, but they can be browsed and exported.
+func (c *Cache) Import(r io.Reader, format string, f Filter, policy ConflictPolicy, ttl time.Duration) (ImportResult, error) {
+	switch policy {
+	case KeepNewest, KeepExisting, Overwrite:
+	default:
+		return ImportResult{}, fmt.Errorf("unknown conflict policy: %q", policy)
+	}
+
+	var res ImportResult
+	put := func(rec Record) error {
+		if !f.Match(&rec.Entry) {
+			res.Skipped++
+			return nil
+		}
+		if !isKey(rec.Key) {
+			rec.Key = GenerateKey(rec.Provider, rec.Model, rec.SourceLang, rec.TargetLang, rec.SourceText)
+		}
+		stored, err := c.put(rec, policy, ttl)
+		if err != nil {
+			return err
+		}
+		if stored {
+			res.Imported++
+		} else {
+			res.Skipped++
+		}
+		return nil
+	}
+
+	var err error
+	switch format {
+	case FormatJSONL:
+		err = readJSONL(r, put)
+	case FormatTMX:
+		err = readTMX(r, put)
+	default:
+		return res, fmt.Errorf("unsupported cache format: %s", format)
+	}
+	if err != nil {
+		return res, fmt.Errorf("import cache: %w", err)
+	}
+	return res, nil
+}
+
+// isKey reports whether s has the form of a key made by GenerateKey.
+func isKey(s string) bool {
+	if len(s) != 2*sha256.Size {
+		return false
+	}
+	_, err := hex.DecodeString(s)
+	return err == nil
+}
+
+// put stores rec with the given TTL unless policy keeps the entry already
+// cached under its key, and reports whether it was stored.
+func (c *Cache) put(rec Record, policy ConflictPolicy, ttl time.Duration) (bool, error) {
+	data, err := json.Marshal(&rec.Entry)
+	if err != nil {
+		return false, fmt.Errorf("marshal entry: %w", err)
+	}
+
+	stored := false
+	var d delta
+	err = c.db.Update(func(txn *badger.Txn) error {
+		if policy != Overwrite {
+			item, err := txn.Get([]byte(rec.Key))
+			switch {
+			case errors.Is(err, badger.ErrKeyNotFound):
+			case err != nil:
+				return err
+			case policy == KeepExisting:
+				return nil
+			default:
+				var existing Entry
+				if err := item.Value(func(val []byte) error {
+					return json.Unmarshal(val, &existing)
+				}); err == nil && !rec.CreatedAt.After(existing.CreatedAt) {
+					return nil
+				}
+			}
+		}
+		stored = true
+		var err error
+		d, err = setEntry(txn, rec.Key, data, expiry(ttl))
+		return err
+	})
+	if err != nil {
+		return false, err
+	}
+	c.apply(d)
+	if stored {
+		c.l1.remove(rec.Key)
+	}
+	return stored, nil
+}
+
+func readJSONL(r io.Reader, put func(Record) error) error {
+	dec := json.NewDecoder(r)
+	for line := 1; ; line++ {
+		var rec Record
+		if err := dec.Decode(&rec); err == io.EOF {
+			return nil
+		} else if err != nil {
+			return fmt.Errorf("record %d: %w", line, err)
+		}
+		if rec.Text == "" {
+			return fmt.Errorf("record %d: missing text", line)
+		}
+		if err := put(rec); err != nil {
+			return err
+		}
+	}
+}
+
+// tmxTimeLayout is the TMX date format, always in UTC.
+const tmxTimeLayout = "20060102T150405Z"
+
+// Names of the TMX properties holding Entry fields that TMX has no
+// attribute for.
+const (
+	propProvider         = "x-provider"
+	propModel            = "x-model"
+	propPromptTokens     = "x-prompt-tokens"
+	propCompletionTokens = "x-completion-tokens"
+)
+
+type tmxHeader struct {
+	XMLName             xml.Name `xml:"header"`
+	CreationTool        string   `xml:"creationtool,attr"`
+	CreationToolVersion string   `xml:"creationtoolversion,attr"`
+	SegType             string   `xml:"segtype,attr"`
+	OTMF                string   `xml:"o-tmf,attr"`
+	AdminLang           string   `xml:"adminlang,attr"`
+	SrcLang             string   `xml:"srclang,attr"`
+	DataType            string   `xml:"datatype,attr"`
+	CreationDate        string   `xml:"creationdate,attr,omitempty"`
+}
+
+type tmxTU struct {
+	XMLName      xml.Name  `xml:"tu"`
+	TUID         string    `xml:"tuid,attr,omitempty"`
+	SrcLang      string    `xml:"srclang,attr,omitempty"`
+	CreationDate string    `xml:"creationdate,attr,omitempty"`
+	Props        []tmxProp `xml:"prop"`
+	TUVs         []tmxTUV  `xml:"tuv"`
+}
+
+type tmxProp struct {
+	Type  string `xml:"type,attr"`
+	Value string `xml:",chardata"`
+}
+
+type tmxTUV struct {
+	Lang       string `xml:"http://www.w3.org/XML/1998/namespace lang,attr,omitempty"`
+	LegacyLang string `xml:"lang,attr,omitempty"` // TMX 1.1
+	Seg        string `xml:"seg"`
+}
+
+func (v tmxTUV) lang() string {
+	if v.Lang != "" {
+		return v.Lang
+	}
+	return v.LegacyLang
+}
+
+// tmxWriter streams translation units into a TMX document.
+type tmxWriter struct {
+	w   io.Writer
+	enc *xml.Encoder
+}
+
+func newTMXWriter(w io.Writer) (*tmxWriter, error) {
+	if _, err := io.WriteString(w, xml.Header+`<tmx version="1.4">`+"\n"); err != nil {
+		return nil, err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("  ", "  ")
+	err := enc.Encode(tmxHeader{
+		CreationTool:        "Transy",
+		CreationToolVersion: "1",
+		SegType:             "sentence",
+		OTMF:                "transy-cache",
+		AdminLang:           "en",
+		SrcLang:             "*all*",
+		DataType:            "plaintext",
+		CreationDate:        time.Now().UTC().Format(tmxTimeLayout),
+	})
+	if err == nil {
+		err = enc.EncodeToken(xml.StartElement{Name: xml.Name{Local: "body"}})
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &tmxWriter{w: w, enc: enc}, nil
+}
+
+func (t *tmxWriter) write(rec Record) error {
+	tu := tmxTU{
+		TUID:    rec.Key,
+		SrcLang: rec.SourceLang,
+		TUVs: []tmxTUV{
+			{Lang: rec.SourceLang, Seg: rec.SourceText},
+			{Lang: rec.TargetLang, Seg: rec.Text},
+		},
+	}
+	if !rec.CreatedAt.IsZero() {
+		tu.CreationDate = rec.CreatedAt.UTC().Format(tmxTimeLayout)
+	}
+	for _, p := range []tmxProp{
+		{propProvider, rec.Provider},
+		{propModel, rec.Model},
+		{propPromptTokens, strconv.Itoa(rec.Usage.PromptTokens)},
+		{propCompletionTokens, strconv.Itoa(rec.Usage.CompletionTokens)},
+	} {
+		if p.Value != "" && p.Value != "0" {
+			tu.Props = append(tu.Props, p)
+		}
+	}
+	return t.enc.Encode(tu)
+}
+
+func (t *tmxWriter) close() error {
+	if err := t.enc.EncodeToken(xml.EndElement{Name: xml.Name{Local: "body"}}); err != nil {
+		return err
+	}
+	if err := t.enc.Flush(); err != nil {
+		return err
+	}
+	_, err := io.WriteString(t.w, "\n</tmx>\n")
+	return err
+}
+
+// readTMX calls put for each translation unit with text in two languages.
+// The source is the variant in the unit's or header's source language, or
+// the first one; the target is the first other variant. Units in more than
+// two languages yield only that pair.
+func readTMX(r io.Reader, put func(Record) error) error {
+	dec := xml.NewDecoder(r)
+	srcLang := ""
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		start, ok := tok.(xml.StartElement)
+		if !ok {
+			continue
+		}
+
+		switch start.Name.Local {
+		case "header":
+			var h tmxHeader
+			if err := dec.DecodeElement(&h, &start); err != nil {
+				return fmt.Errorf("header: %w", err)
+			}
+			if h.SrcLang != "*all*" {
+				srcLang = h.SrcLang
+			}
+		case "tu":
+			var tu tmxTU
+			if err := dec.DecodeElement(&tu, &start); err != nil {
+				return fmt.Errorf("translation unit: %w", err)
+			}
+			if rec, ok := tu.record(srcLang); ok {
+				if err := put(rec); err != nil {
+					return err
+				}
+			}
+		}
+	}
+}
+
+// record converts the unit, reporting false if it lacks a language pair.
+func (tu tmxTU) record(headerSrcLang string) (Record, bool) {
+	if len(tu.TUVs) < 2 {
+		return Record{}, false
+	}
+	srcLang := tu.SrcLang
+	if srcLang == "" || srcLang == "*all*" {
+		srcLang = headerSrcLang
+	}
+	src := 0
+	for i, v := range tu.TUVs {
+		if strings.EqualFold(v.lang(), srcLang) {
+			src = i
+			break
+		}
+	}
+	tgt := 0
+	if src == 0 {
+		tgt = 1
+	}
+
+	rec := Record{
+		Key: tu.TUID,
+		Entry: Entry{
+			SourceText: tu.TUVs[src].Seg,
+			SourceLang: tu.TUVs[src].lang(),
+			Text:       tu.TUVs[tgt].Seg,
+			TargetLang: tu.TUVs[tgt].lang(),
+		},
+	}
+	if rec.SourceText == "" || rec.Text == "" {
+		return Record{}, false
+	}
+	if t, err := time.Parse(tmxTimeLayout, tu.CreationDate); err == nil {
+		rec.CreatedAt = t
+	}
+	for _, p := range tu.Props {
+		switch p.Type {
+		case propProvider:
+			rec.Provider = p.Value
+		case propModel:
+			rec.Model = p.Value
+		case propPromptTokens:
+			rec.Usage.PromptTokens, _ = strconv.Atoi(p.Value)
+		case propCompletionTokens:
+			rec.Usage.CompletionTokens, _ = strconv.Atoi(p.Value)
+		}
+	}
+	rec.Usage.TotalTokens = rec.Usage.PromptTokens + rec.Usage.CompletionTokens
+	return rec, true
+}
@@ -0,0 +1,199 @@
+package cache
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newTestCache opens a cache in a fresh directory, closed when the test
+// ends.
+func newTestCache(t testing.TB, opts ...Option) *Cache {
+	t.Helper()
+	c := openTestCache(t, filepath.Join(t.TempDir(), "cache"), opts...)
+	t.Cleanup(func() { c.Close() })
+	return c
+}
+
+// openTestCache opens the cache at path for tests that close and reopen
+// it, which close it themselves.
+func openTestCache(t testing.TB, path string, opts ...Option) *Cache {
+	t.Helper()
+	c, err := New(path, opts...)
+	if err != nil {
+		t.Fatalf("open cache: %v", err)
+	}
+	return c
+}
+
+func TestExportImportRoundTrip(t *testing.T) {
+	created := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	entries := []Entry{
+		{SourceText: "Hello", Text: "你好", SourceLang: "en", TargetLang: "zh", Provider: "openai", Model: "gpt-4",
+			Usage: Usage{PromptTokens: 10, CompletionTokens: 2, TotalTokens: 12}, CreatedAt: created},
+		{SourceText: "Bonjour", Text: "Hello", SourceLang: "fr", TargetLang: "en", Provider: "claude", Model: "sonnet",
+			CreatedAt: created.Add(24 * time.Hour)},
+		{Text: "an image", SourceLang: "ja", TargetLang: "en", Provider: "openai", CreatedAt: created},
+	}
+
+	for _, format := range []string{FormatJSONL, FormatTMX} {
+		t.Run(format, func(t *testing.T) {
+			src := newTestCache(t)
+			for _, e := range entries {
+				key := GenerateKey(e.Provider, e.Model, e.SourceLang, e.TargetLang, e.SourceText+e.Text, "prompt")
+				if err := src.Set(key, &e, DefaultTTL); err != nil {
+					t.Fatalf("set: %v", err)
+				}
+			}
+
+			var buf bytes.Buffer
+			n, err := src.Export(&buf, format, Filter{})
+			if err != nil {
+				t.Fatalf("export: %v", err)
+			}
+			want := len(entries)
+			if format == FormatTMX {
+				want-- // the image has no source text
+			}
+			if n != want {
+				t.Errorf("exported %d entries, want %d", n, want)
+			}
+
+			dst := newTestCache(t)
+			res, err := dst.Import(&buf, format, Filter{}, KeepNewest, 0)
+			if err != nil {
+				t.Fatalf("import: %v", err)
+			}
+			if res.Imported != want || res.Skipped != 0 {
+				t.Errorf("import result = %+v, want %d imported", res, want)
+			}
+
+			got, _ := src.Search("", 0)
+			for _, rec := range got {
+				if rec.SourceText == "" && format == FormatTMX {
+					continue
+				}
+				e, found := dst.Get(rec.Key)
+				if !found {
+					t.Errorf("%q not imported under its key", rec.SourceText)
+					continue
+				}
+				if e.Text != rec.Text || e.SourceLang != rec.SourceLang || e.Provider != rec.Provider ||
+					e.Model != rec.Model || e.Usage != rec.Usage || !e.CreatedAt.Equal(rec.CreatedAt) {
+					t.Errorf("imported %+v, want %+v", *e, rec.Entry)
+				}
+			}
+		})
+	}
+}
+
+func TestExportFilter(t *testing.T) {
+	c := newTestCache(t)
+	day := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	for _, e := range []Entry{
+		{SourceText: "a", Text: "A", SourceLang: "en", TargetLang: "zh", Provider: "openai", CreatedAt: day},
+		{SourceText: "b", Text: "B", SourceLang: "en", TargetLang: "ja", Provider: "openai", CreatedAt: day.Add(24 * time.Hour)},
+		{SourceText: "c", Text: "C", SourceLang: "en", TargetLang: "zh", Provider: "claude", CreatedAt: day.Add(48 * time.Hour)},
+	} {
+		c.Set(GenerateKey("p", "m", "en", "zh", e.SourceText), &e, DefaultTTL)
+	}
+
+	tests := []struct {
+		name   string
+		filter Filter
+		want   int
+	}{
+		{"all", Filter{}, 3},
+		{"target language", Filter{SourceLang: "EN", TargetLang: "zh"}, 2},
+		{"provider", Filter{Provider: "openai"}, 2},
+		{"since", Filter{Since: day.Add(24 * time.Hour)}, 2},
+		{"until is exclusive", Filter{Until: day.Add(24 * time.Hour)}, 1},
+		{"combined", Filter{Provider: "openai", TargetLang: "zh", Since: day.Add(time.Hour)}, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			n, err := c.Export(&bytes.Buffer{}, FormatJSONL, tt.filter)
+			if err != nil {
+				t.Fatalf("export: %v", err)
+			}
+			if n != tt.want {
+				t.Errorf("exported %d entries, want %d", n, tt.want)
+			}
+		})
+	}
+}
+
+func TestImportConflictPolicy(t *testing.T) {
+	key := GenerateKey("p", "m", "en", "zh", "hello")
+	old := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	line := func(text string, created time.Time) string {
+		return `{"key":"` + key + `","text":"` + text + `","created_at":"` + created.Format(time.RFC3339) + `"}` + "\n"
+	}
+
+	tests := []struct {
+		name   string
+		policy ConflictPolicy
+		input  string
+		want   string
+	}{
+		{"newest replaces older", KeepNewest, line("newer", old.Add(time.Hour)), "newer"},
+		{"newest keeps newer", KeepNewest, line("older", old.Add(-time.Hour)), "cached"},
+		{"existing", KeepExisting, line("newer", old.Add(time.Hour)), "cached"},
+		{"overwrite", Overwrite, line("older", old.Add(-time.Hour)), "older"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newTestCache(t)
+			c.Set(key, &Entry{Text: "cached", CreatedAt: old}, DefaultTTL)
+
+			if _, err := c.Import(strings.NewReader(tt.input), FormatJSONL, Filter{}, tt.policy, 0); err != nil {
+				t.Fatalf("import: %v", err)
+			}
+			e, _ := c.Get(key)
+			if e.Text != tt.want {
+				t.Errorf("text = %q, want %q", e.Text, tt.want)
+			}
+		})
+	}
+
+	c := newTestCache(t)
+	if _, err := c.Import(strings.NewReader(""), FormatJSONL, Filter{}, "merge", 0); err == nil {
+		t.Error("expected error for unknown policy")
+	}
+}
+
+func TestImportForeignTMX(t *testing.T) {
+	const doc = `<?xml version="1.0" encoding="UTF-8"?>
+<tmx version="1.4">
+  <header creationtool="OtherTool" creationtoolversion="2" segtype="sentence" o-tmf="x" adminlang="en-US" srclang="de" datatype="plaintext"/>
+  <body>
+    <tu tuid="17">
+      <tuv xml:lang="en"><seg>Hello</seg></tuv>
+      <tuv xml:lang="de"><seg>Hallo</seg></tuv>
+    </tu>
+    <tu>
+      <tuv xml:lang="de"><seg>Nur eine Sprache</seg></tuv>
+    </tu>
+  </body>
+</tmx>`
+
+	c := newTestCache(t)
+	res, err := c.Import(strings.NewReader(doc), FormatTMX, Filter{}, KeepNewest, 0)
+	if err != nil {
+		t.Fatalf("import: %v", err)
+	}
+	if res.Imported != 1 {
+		t.Fatalf("imported %d units, want 1", res.Imported)
+	}
+
+	recs, _ := c.Search("", 0)
+	rec := recs[0]
+	if rec.SourceLang != "de" || rec.SourceText != "Hallo" || rec.TargetLang != "en" || rec.Text != "Hello" {
+		t.Errorf("record = %+v, want de Hallo → en Hello", rec.Entry)
+	}
+	if rec.Key != GenerateKey("", "", "de", "en", "Hallo") {
+		t.Errorf("foreign tuid used as key: %q", rec.Key)
+	}
+}
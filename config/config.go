@@ -7,8 +7,13 @@ import (
 	"os"
 	"path/filepath"
 	"slices"
+	"time"
 
+	"go.aimuz.me/transy/cache"
 	"go.aimuz.me/transy/internal/types"
+	"go.aimuz.me/transy/llm"
+	"go.aimuz.me/transy/prompt"
+	"go.aimuz.me/transy/telemetry"
 )
 
 const (
@@ -21,8 +26,51 @@ const (
 type Config struct {
 	Providers        []types.Provider  `json:"providers"`
 	DefaultLanguages map[string]string `json:"default_languages"`
+	// Fallbacks lists provider names, in order, to try when the active
+	// provider fails with a transient or quota error.
+	Fallbacks []string `json:"fallbacks,omitempty"`
+	// PromptTemplates are the user-defined translation instructions.
+	PromptTemplates []prompt.Template `json:"prompt_templates,omitempty"`
+	// PromptRules select a template by language pair, taking precedence
+	// over the provider's template. The first matching rule wins.
+	PromptRules []prompt.Rule `json:"prompt_rules,omitempty"`
+	// Memory controls the translation memory.
+	Memory MemorySettings `json:"memory"`
+	// Verification selects the providers that check translations.
+	Verification VerificationSettings `json:"verification"`
+	// Telemetry selects where traces and metrics are exported.
+	Telemetry telemetry.Settings `json:"telemetry"`
+	// Cache controls how long translations are cached and how many are kept.
+	Cache CacheSettings `json:"cache"`
+	// DebugLog records every LLM request and response, with credentials
+	// redacted, to a rotating log file.
+	DebugLog bool `json:"debug_log,omitempty"`
 }
 
+// MemorySettings controls how past translations are recorded and reused.
+type MemorySettings struct {
+	Disabled bool    `json:"disabled,omitempty"`  // neither record nor look up translations
+	Examples int     `json:"examples,omitempty"`  // best matches fed to the prompt as few-shot examples; 0 feeds none
+	MinScore float64 `json:"min_score,omitempty"` // similarity a match needs, in (0, 1]; 0 uses the default
+}
+
+// VerificationSettings selects the providers used when a translation is
+// verified. An empty name uses the provider that made the translation.
+type VerificationSettings struct {
+	BackTranslator string `json:"back_translator,omitempty"` // translates the output back into the source language
+	Judge          string `json:"judge,omitempty"`           // scores the translation and lists its errors
+}
+
+// CacheSettings controls the translation cache. Providers may override TTL.
+type CacheSettings struct {
+	TTL        int `json:"ttl,omitempty"`         // hours translations are cached; 0 uses the default of 7 days, -1 keeps them until evicted
+	MaxEntries int `json:"max_entries,omitempty"` // entries kept before the least recently used are evicted; 0 means no limit
+	MaxSizeMB  int `json:"max_size_mb,omitempty"` // megabytes of entries kept before the least recently used are evicted; 0 means no limit
+}
+
+// maxMemoryExamples bounds the few-shot examples to keep prompts small.
+const maxMemoryExamples = 10
+
 // Load loads configuration from the config file.
 // Returns default config if file doesn't exist.
 func Load() (*Config, error) {
@@ -83,7 +131,7 @@ func (c *Config) Save() error {
 
 // AddProvider adds a new provider.
 func (c *Config) AddProvider(p types.Provider) error {
-	if err := validateProvider(p); err != nil {
+	if err := c.validateProvider(p); err != nil {
 		return err
 	}
 	applyDefaults(&p)
@@ -102,7 +150,7 @@ func (c *Config) AddProvider(p types.Provider) error {
 
 // UpdateProvider updates an existing provider.
 func (c *Config) UpdateProvider(name string, p types.Provider) error {
-	if err := validateProvider(p); err != nil {
+	if err := c.validateProvider(p); err != nil {
 		return err
 	}
 	applyDefaults(&p)
@@ -124,6 +172,14 @@ func (c *Config) UpdateProvider(name string, p types.Provider) error {
 	}
 
 	c.Providers[idx] = p
+	if p.Name != name {
+		for i, f := range c.Fallbacks {
+			if f == name {
+				c.Fallbacks[i] = p.Name
+			}
+		}
+		c.Verification.rename(name, p.Name)
+	}
 	return c.Save()
 }
 
@@ -138,6 +194,10 @@ func (c *Config) RemoveProvider(name string) error {
 
 	wasActive := c.Providers[idx].Active
 	c.Providers = slices.Delete(c.Providers, idx, idx+1)
+	c.Fallbacks = slices.DeleteFunc(c.Fallbacks, func(f string) bool {
+		return f == name
+	})
+	c.Verification.rename(name, "")
 
 	if wasActive && len(c.Providers) > 0 {
 		c.Providers[0].Active = true
@@ -181,22 +241,237 @@ func (c *Config) GetActiveProvider() *types.Provider {
 	return nil
 }
 
+// GetProvider returns a copy of the provider with the given name, or nil.
+func (c *Config) GetProvider(name string) *types.Provider {
+	if idx := c.findProvider(name); idx != -1 {
+		p := c.Providers[idx]
+		return &p
+	}
+	return nil
+}
+
+// SetFallbacks replaces the ordered failover list.
+// Every name must refer to a configured provider and appear at most once.
+func (c *Config) SetFallbacks(names []string) error {
+	for i, name := range names {
+		if c.findProvider(name) == -1 {
+			return fmt.Errorf("provider not found: %s", name)
+		}
+		if slices.Contains(names[:i], name) {
+			return fmt.Errorf("duplicate fallback provider: %s", name)
+		}
+	}
+	c.Fallbacks = slices.Clone(names)
+	return c.Save()
+}
+
+// ProviderChain returns the providers to try for a translation: the active
+// provider followed by the configured fallbacks, skipping the active one and
+// any that no longer exist.
+func (c *Config) ProviderChain() []types.Provider {
+	active := c.GetActiveProvider()
+	if active == nil {
+		return nil
+	}
+
+	chain := []types.Provider{*active}
+	for _, name := range c.Fallbacks {
+		if name == active.Name {
+			continue
+		}
+		if idx := c.findProvider(name); idx != -1 {
+			chain = append(chain, c.Providers[idx])
+		}
+	}
+	return chain
+}
+
+// PromptTemplate returns the template to translate from sourceLang to
+// targetLang with p: the first matching rule's, else the provider's, else
+// the default.
+func (c *Config) PromptTemplate(p *types.Provider, sourceLang, targetLang string) prompt.Template {
+	for _, r := range c.PromptRules {
+		if r.Matches(sourceLang, targetLang) {
+			if t, ok := c.lookupTemplate(r.Template); ok {
+				return t
+			}
+		}
+	}
+	if t, ok := c.lookupTemplate(p.PromptTemplate); ok {
+		return t
+	}
+	t, _ := c.lookupTemplate(prompt.DefaultID)
+	return t
+}
+
+// SavePromptTemplate adds the template, or replaces the one with the same ID.
+func (c *Config) SavePromptTemplate(t prompt.Template) error {
+	if err := t.Validate(); err != nil {
+		return err
+	}
+
+	idx := c.findTemplate(t.ID)
+	if idx == -1 {
+		c.PromptTemplates = append(c.PromptTemplates, t)
+	} else {
+		c.PromptTemplates[idx] = t
+	}
+	return c.Save()
+}
+
+// RemovePromptTemplate removes a template, along with the rules that select
+// it. Providers using it fall back to the default.
+func (c *Config) RemovePromptTemplate(id string) error {
+	idx := c.findTemplate(id)
+	if idx == -1 {
+		return fmt.Errorf("prompt template not found: %s", id)
+	}
+
+	c.PromptTemplates = slices.Delete(c.PromptTemplates, idx, idx+1)
+	c.PromptRules = slices.DeleteFunc(c.PromptRules, func(r prompt.Rule) bool {
+		return r.Template == id
+	})
+	for i := range c.Providers {
+		if c.Providers[i].PromptTemplate == id {
+			c.Providers[i].PromptTemplate = ""
+		}
+	}
+	return c.Save()
+}
+
+// SetPromptRules replaces the language pair rules.
+// Every rule must refer to an existing template.
+func (c *Config) SetPromptRules(rules []prompt.Rule) error {
+	for _, r := range rules {
+		if _, ok := c.lookupTemplate(r.Template); !ok {
+			return fmt.Errorf("prompt template not found: %s", r.Template)
+		}
+	}
+	c.PromptRules = slices.Clone(rules)
+	return c.Save()
+}
+
+// SetMemorySettings replaces the translation memory settings.
+func (c *Config) SetMemorySettings(m MemorySettings) error {
+	if m.Examples < 0 || m.Examples > maxMemoryExamples {
+		return fmt.Errorf("memory examples must be between 0 and %d", maxMemoryExamples)
+	}
+	if m.MinScore < 0 || m.MinScore > 1 {
+		return fmt.Errorf("memory min score must be between 0 and 1")
+	}
+	c.Memory = m
+	return c.Save()
+}
+
+// SetVerificationSettings replaces the verification settings.
+func (c *Config) SetVerificationSettings(v VerificationSettings) error {
+	for _, name := range []string{v.BackTranslator, v.Judge} {
+		if name != "" && c.findProvider(name) == -1 {
+			return fmt.Errorf("provider not found: %s", name)
+		}
+	}
+	c.Verification = v
+	return c.Save()
+}
+
+// SetTelemetrySettings replaces the telemetry settings.
+func (c *Config) SetTelemetrySettings(t telemetry.Settings) error {
+	if err := t.Validate(); err != nil {
+		return err
+	}
+	c.Telemetry = t
+	return c.Save()
+}
+
+// SetCacheSettings replaces the translation cache settings.
+func (c *Config) SetCacheSettings(cs CacheSettings) error {
+	if cs.TTL < -1 {
+		return fmt.Errorf("cache TTL must be -1 (never expire) or more")
+	}
+	if cs.MaxEntries < 0 || cs.MaxSizeMB < 0 {
+		return fmt.Errorf("cache limits must not be negative")
+	}
+	c.Cache = cs
+	return c.Save()
+}
+
+// CacheTTL returns how long translations by p are cached: p's own TTL if
+// set, else the global one. A nil p gets the global TTL.
+func (c *Config) CacheTTL(p *types.Provider) time.Duration {
+	hours := c.Cache.TTL
+	if p != nil && p.CacheTTL != 0 {
+		hours = p.CacheTTL
+	}
+	switch {
+	case hours < 0:
+		return cache.NeverExpire
+	case hours == 0:
+		return cache.DefaultTTL
+	default:
+		return time.Duration(hours) * time.Hour
+	}
+}
+
+// SetDebugLog turns debug logging of LLM calls on or off.
+func (c *Config) SetDebugLog(enabled bool) error {
+	c.DebugLog = enabled
+	return c.Save()
+}
+
+// rename updates references to a renamed provider; an empty name clears them.
+func (v *VerificationSettings) rename(old, name string) {
+	if v.BackTranslator == old {
+		v.BackTranslator = name
+	}
+	if v.Judge == old {
+		v.Judge = name
+	}
+}
+
 // Helper functions
 
-func validateProvider(p types.Provider) error {
+func (c *Config) findProvider(name string) int {
+	return slices.IndexFunc(c.Providers, func(p types.Provider) bool {
+		return p.Name == name
+	})
+}
+
+func (c *Config) findTemplate(id string) int {
+	return slices.IndexFunc(c.PromptTemplates, func(t prompt.Template) bool {
+		return t.ID == id
+	})
+}
+
+// lookupTemplate finds a configured template, or the built-in default.
+func (c *Config) lookupTemplate(id string) (prompt.Template, bool) {
+	if idx := c.findTemplate(id); idx != -1 {
+		return c.PromptTemplates[idx], true
+	}
+	if id == prompt.DefaultID {
+		return prompt.Default, true
+	}
+	return prompt.Template{}, false
+}
+
+func (c *Config) validateProvider(p types.Provider) error {
 	if p.Name == "" {
 		return fmt.Errorf("provider name required")
 	}
-	if p.APIKey == "" {
-		return fmt.Errorf("api key required")
+	if p.PromptTemplate != "" {
+		if _, ok := c.lookupTemplate(p.PromptTemplate); !ok {
+			return fmt.Errorf("prompt template not found: %s", p.PromptTemplate)
+		}
 	}
-	if p.Model == "" {
-		return fmt.Errorf("model required")
+	switch p.ScreenshotMode {
+	case "", types.ScreenshotModeOCR, types.ScreenshotModeVision:
+	default:
+		return fmt.Errorf("invalid screenshot mode %q", p.ScreenshotMode)
 	}
-	if p.Type == "openai-compatible" && p.BaseURL == "" {
-		return fmt.Errorf("base url required for openai-compatible")
+	if p.CacheTTL < -1 {
+		return fmt.Errorf("cache TTL must be -1 (never expire) or more")
 	}
-	return nil
+	// Type-specific checks are owned by the backend registered in llm.
+	return llm.Validate(&p)
 }
 
 func applyDefaults(p *types.Provider) {
@@ -1,5 +1,26 @@
 import * as App from '@wailsjs/go/main/App'
-import type { Provider, TranslateRequest, DetectLanguageResponse, TranslateResult } from '../types'
+import type {
+  Provider,
+  ProviderTypeInfo,
+  PromptTemplate,
+  PromptRule,
+  GlossaryEntry,
+  CacheSettings,
+  CacheReport,
+  CacheRecord,
+  CacheFilter,
+  CacheConflictPolicy,
+  CacheImportResult,
+  MemorySettings,
+  VerificationSettings,
+  TelemetrySettings,
+  TranslateRequest,
+  DetectLanguageResponse,
+  TranslateResult,
+  CompareResult,
+  TranslateError,
+  TranslateErrorCode,
+} from '../types'
 
 // Provider management
 export async function getProviders(): Promise<Provider[]> {
@@ -27,11 +48,173 @@ export async function getActiveProvider(): Promise<Provider | null> {
   return (await App.GetActiveProvider()) as Provider | null
 }
 
+export async function getFallbacks(): Promise<string[]> {
+  return (await App.GetFallbacks()) || []
+}
+
+export async function setFallbacks(names: string[]): Promise<void> {
+  await App.SetFallbacks(names)
+}
+
+export async function listModels(provider: Provider): Promise<string[]> {
+  return (await App.ListModels(provider)) || []
+}
+
+export async function getProviderTypes(): Promise<ProviderTypeInfo[]> {
+  return ((await App.GetProviderTypes()) || []) as ProviderTypeInfo[]
+}
+
+// Prompt templates
+export async function getPromptTemplates(): Promise<PromptTemplate[]> {
+  return (await App.GetPromptTemplates()) || []
+}
+
+export async function savePromptTemplate(template: PromptTemplate): Promise<void> {
+  await App.SavePromptTemplate(template)
+}
+
+export async function removePromptTemplate(id: string): Promise<void> {
+  await App.RemovePromptTemplate(id)
+}
+
+export async function getPromptRules(): Promise<PromptRule[]> {
+  return (await App.GetPromptRules()) || []
+}
+
+export async function setPromptRules(rules: PromptRule[]): Promise<void> {
+  await App.SetPromptRules(rules)
+}
+
+// Glossary
+export async function getGlossary(): Promise<GlossaryEntry[]> {
+  return (await App.GetGlossary()) || []
+}
+
+export async function putGlossaryEntry(entry: GlossaryEntry): Promise<void> {
+  await App.PutGlossaryEntry(entry)
+}
+
+export async function removeGlossaryEntry(entry: GlossaryEntry): Promise<void> {
+  await App.RemoveGlossaryEntry(entry)
+}
+
+// Returns the number of imported entries, or 0 if the user cancelled
+export async function importGlossary(): Promise<number> {
+  return await App.ImportGlossary()
+}
+
+// Returns the exported file path, or '' if the user cancelled
+export async function exportGlossary(): Promise<string> {
+  return await App.ExportGlossary()
+}
+
+// Translation cache
+export async function getCacheSettings(): Promise<CacheSettings> {
+  return await App.GetCacheSettings()
+}
+
+export async function setCacheSettings(settings: CacheSettings): Promise<void> {
+  await App.SetCacheSettings(settings)
+}
+
+export async function getCacheStats(): Promise<CacheReport> {
+  const report = await App.GetCacheStats()
+  return { ...report, groups: report.groups || [] } as CacheReport
+}
+
+export async function resetCacheStats(): Promise<void> {
+  await App.ResetCacheStats()
+}
+
+export async function searchCache(query: string): Promise<CacheRecord[]> {
+  return ((await App.SearchCache(query)) || []) as CacheRecord[]
+}
+
+export async function searchCachePrefix(prefix: string): Promise<CacheRecord[]> {
+  return ((await App.SearchCachePrefix(prefix)) || []) as CacheRecord[]
+}
+
+export async function deleteCacheEntry(key: string): Promise<void> {
+  await App.DeleteCacheEntry(key)
+}
+
+// Returns the exported file path, or '' if the user cancelled
+export async function exportCache(filter: CacheFilter): Promise<string> {
+  return await App.ExportCache(filter)
+}
+
+// Returns zero counts if the user cancelled
+export async function importCache(
+  filter: CacheFilter,
+  policy: CacheConflictPolicy
+): Promise<CacheImportResult> {
+  return await App.ImportCache(filter, policy)
+}
+
+// Translation memory
+export async function getMemorySettings(): Promise<MemorySettings> {
+  return await App.GetMemorySettings()
+}
+
+export async function setMemorySettings(settings: MemorySettings): Promise<void> {
+  await App.SetMemorySettings(settings)
+}
+
+// Verification
+export async function getVerificationSettings(): Promise<VerificationSettings> {
+  return await App.GetVerificationSettings()
+}
+
+export async function setVerificationSettings(settings: VerificationSettings): Promise<void> {
+  await App.SetVerificationSettings(settings)
+}
+
+// Telemetry
+export async function getTelemetrySettings(): Promise<TelemetrySettings> {
+  return await App.GetTelemetrySettings()
+}
+
+export async function setTelemetrySettings(settings: TelemetrySettings): Promise<void> {
+  await App.SetTelemetrySettings(settings)
+}
+
+// Debug logging
+export async function getDebugLogging(): Promise<boolean> {
+  return await App.GetDebugLogging()
+}
+
+export async function setDebugLogging(enabled: boolean): Promise<void> {
+  await App.SetDebugLogging(enabled)
+}
+
+export async function getDebugLogPath(): Promise<string> {
+  return await App.GetDebugLogPath()
+}
+
 // Translation
 export async function translateWithLLM(request: TranslateRequest): Promise<TranslateResult> {
   return await App.TranslateWithLLM(request)
 }
 
+export async function translateCompare(
+  request: TranslateRequest,
+  providerNames: string[]
+): Promise<CompareResult[]> {
+  return ((await App.TranslateCompare(request, providerNames)) || []) as CompareResult[]
+}
+
+// Revise a translation; deltas are streamed with the session ID as request ID
+export async function refineTranslation(
+  sessionId: string,
+  instruction: string
+): Promise<TranslateResult> {
+  return await App.RefineTranslation(sessionId, instruction)
+}
+
+export async function cancelTranslation(id: string): Promise<void> {
+  await App.CancelTranslation(id)
+}
+
 export async function detectLanguage(text: string): Promise<DetectLanguageResponse> {
   return await App.DetectLanguage(text)
 }
@@ -61,3 +244,28 @@ export async function takeScreenshotAndOCR(): Promise<string> {
   // @ts-ignore - TakeScreenshotAndOCR is generated by Wails
   return await App.TakeScreenshotAndOCR()
 }
+
+// Errors
+const ERROR_MESSAGES: Partial<Record<TranslateErrorCode, string>> = {
+  auth: 'API Key 无效或无权限，请检查服务商配置',
+  rate_limited: '请求过于频繁，请稍后再试',
+  quota_exhausted: '额度已用尽，请检查账户余额',
+  content_blocked: '内容被服务商安全策略拦截',
+  transient: '网络或服务暂时不可用，请稍后重试',
+  bad_request: '请求无效，请检查模型名称等配置',
+}
+
+export function isTranslateError(error: unknown): error is TranslateError {
+  return typeof error === 'object' && error !== null && 'code' in error && 'message' in error
+}
+
+// errorMessage returns a user-facing message for an error thrown by a binding
+export function errorMessage(error: unknown): string {
+  if (!isTranslateError(error)) return String(error)
+  const friendly = ERROR_MESSAGES[error.code]
+  if (!friendly) return error.message
+  if (error.retryAfterMs) {
+    return `${friendly}（${Math.ceil(error.retryAfterMs / 1000)} 秒后）`
+  }
+  return friendly
+}
@@ -3,7 +3,7 @@
 
 export type Provider = {
   name: string
-  type: 'openai' | 'openai-compatible' | 'gemini' | 'claude'
+  type: 'openai' | 'openai-compatible' | 'gemini' | 'claude' | 'ollama' | 'azure-openai'
   base_url?: string
   api_key: string
   model: string
@@ -11,13 +11,57 @@ export type Provider = {
   max_tokens?: number
   temperature?: number
   active: boolean
-  disable_thinking?: boolean // For Gemini: set thinkingBudget to 0
+  disable_thinking?: boolean // For Gemini: set thinkingBudget to 0; ignored when reasoning is set
+  reasoning?: '' | 'off' | 'low' | 'medium' | 'high' // empty uses the model default
+  num_ctx?: number // For Ollama: context window size
+  keep_alive?: string // For Ollama: how long the model stays loaded
+  endpoint?: string // For Azure OpenAI: resource endpoint
+  deployment?: string // For Azure OpenAI: deployment name
+  api_version?: string // For Azure OpenAI: api-version query parameter
+  screenshot_mode?: '' | 'ocr' | 'vision' // how screenshots are translated; empty means OCR
+  prompt_template?: string // ID of the prompt template; empty uses the default
+  cache_ttl?: number // hours translations are cached; 0 uses the global setting, -1 never expires
+  proxy?: string // http://, https:// or socks5:// proxy URL
+  ca_cert_file?: string // PEM bundle trusted in addition to the system roots
+  client_cert_file?: string // PEM client certificate for mutual TLS
+  client_key_file?: string // PEM private key for client_cert_file
+  request_timeout?: number // seconds to wait for each attempt's response headers
+  timeout?: number // seconds for the whole call including retries
+  headers?: Record<string, string> // extra HTTP headers
+  extra_body?: Record<string, unknown> // merged into the JSON request body
+}
+
+// Go text/template with .SourceLang, .TargetLang, .Text, .Domain, .Tone and .Glossary
+export type PromptTemplate = {
+  id: string
+  name: string
+  text: string
+  domain?: string
+  tone?: string
+}
+
+// Selects a template by language pair; an empty language matches any
+export type PromptRule = {
+  source_lang?: string
+  target_lang?: string
+  template: string
+}
+
+export type ProviderTypeInfo = {
+  type: string
+  capabilities: {
+    streaming: boolean
+    listModels: boolean
+  }
 }
 
 export type TranslateRequest = {
+  id?: string
   text: string
   sourceLang: string
   targetLang: string
+  image?: string // base64 PNG translated by a vision model instead of text
+  verify?: boolean // back-translate and score the result
 }
 
 export type DetectLanguageResponse = {
@@ -35,7 +79,176 @@ export type Usage = {
 
 export type TranslateResult = {
   text: string
+  reasoning?: string // the model's reasoning, kept out of text
+  usage: Usage
+  provider: string // name of the provider that served the result
+  glossaryIssues?: GlossaryIssue[] // glossary terms not applied in text
+  memoryMatches?: MemoryMatch[] // similar earlier translations, best first
+  sessionId?: string // pass to refineTranslation to revise text
+  verification?: Verification // set when the request asked for verification
+}
+
+// Quality estimate from back-translation and an MQM-style judge
+export type Verification = {
+  backTranslation?: string
+  score: number // 0-100; 100 means no errors were found
+  errors?: QualityIssue[]
+  backTranslator?: string
+  judge?: string
+  error?: string // why verification failed; the translation stands
+}
+
+export type QualityIssue = {
+  category: string // e.g. "accuracy/mistranslation"
+  severity: 'minor' | 'major' | 'critical'
+  span: string // the offending text in the translation
+  explanation?: string
+  suggestion?: string
+}
+
+// Where traces and metrics are exported; applied on restart
+export type TelemetrySettings = {
+  exporter?: '' | 'otlp' | 'file' // empty disables telemetry
+  endpoint?: string // OTLP/HTTP base URL, e.g. http://localhost:4318
+  headers?: Record<string, string>
+  file?: string // JSON lines file; empty uses the default path
+}
+
+// Providers used to verify translations; empty uses the translating provider
+export type VerificationSettings = {
+  back_translator?: string
+  judge?: string
+}
+
+// An earlier translation of text similar to the request's
+export type MemoryMatch = {
+  source: string
+  target: string
+  score: number // similarity in (0, 1]; 1 is an exact match
+  provider?: string
+}
+
+export type MemorySettings = {
+  disabled?: boolean // neither record nor look up translations
+  examples?: number // best matches fed to the prompt as few-shot examples
+  min_score?: number // similarity a match needs, in (0, 1]; 0 uses the default
+}
+
+export type CacheSettings = {
+  ttl?: number // hours translations are cached; 0 uses the default of 7 days, -1 never expires
+  max_entries?: number // entries kept before the least recently used are evicted; 0 means no limit
+  max_size_mb?: number // megabytes kept before the least recently used are evicted; 0 means no limit
+}
+
+export type CacheStats = {
+  hits: number
+  misses: number
+  prompt_tokens_saved: number // tokens that hits saved, as recorded in the entries
+  completion_tokens_saved: number
+}
+
+// Cache statistics persisted across restarts, in total and by provider,
+// model and language pair
+export type CacheReport = {
+  since: string // when the statistics were started or last reset
+  total: CacheStats
+  groups: (CacheStats & {
+    provider: string
+    model: string
+    source_lang: string
+    target_lang: string
+  })[] // most hits first
+}
+
+// A cached translation, as listed in the cache browser
+export type CacheRecord = {
+  key: string
+  text: string
+  reasoning?: string
+  usage: {
+    prompt_tokens: number
+    completion_tokens: number
+    total_tokens: number
+  }
+  created_at: string
+  // Empty for images and for entries cached by older versions
+  source_text?: string
+  source_lang?: string
+  target_lang?: string
+  provider?: string
+  model?: string
+}
+
+// Selects cached translations to export or import; empty fields match all
+export type CacheFilter = {
+  source_lang?: string
+  target_lang?: string
+  provider?: string
+  since?: string // RFC 3339, inclusive
+  until?: string // RFC 3339, exclusive
+}
+
+// How an import treats translations that are already cached
+export type CacheConflictPolicy = 'newest' | 'existing' | 'overwrite'
+
+export type CacheImportResult = {
+  imported: number
+  skipped: number // filtered out or kept out by the conflict policy
+}
+
+// A glossary term whose required translation is missing from the output
+export type GlossaryIssue = {
+  source: string
+  expected: string
+}
+
+// Empty languages match any language
+export type GlossaryEntry = {
+  source: string
+  target?: string
+  source_lang?: string
+  target_lang?: string
+  do_not_translate?: boolean // keep the term as is, e.g. product names
+}
+
+export type CompareResult = {
+  provider: string
+  model: string
+  text: string
+  reasoning?: string
   usage: Usage
+  latencyMs: number
+  error?: TranslateError
+}
+
+export type TranslateDelta = {
+  id: string
+  delta: string
+}
+
+export type TranslateDone = {
+  id: string
+  text: string
+  reasoning?: string
+  usage: Usage
+  provider: string
+}
+
+export type TranslateErrorCode =
+  | 'auth'
+  | 'rate_limited'
+  | 'quota_exhausted'
+  | 'content_blocked'
+  | 'transient'
+  | 'bad_request'
+  | 'canceled'
+  | 'unknown'
+
+// Structured error returned by the backend for failed translations
+export type TranslateError = {
+  code: TranslateErrorCode
+  message: string
+  retryAfterMs?: number
 }
 
 export type Language = {
@@ -1,29 +1,105 @@
 // Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
 // This file is automatically generated. DO NOT EDIT
+import {cache} from '../models';
+import {config} from '../models';
+import {glossary} from '../models';
+import {llm} from '../models';
+import {prompt} from '../models';
+import {telemetry} from '../models';
 import {types} from '../models';
 
 export function AddProvider(arg1:types.Provider):Promise<void>;
 
+export function CancelTranslation(arg1:string):Promise<void>;
+
+export function DeleteCacheEntry(arg1:string):Promise<void>;
+
 export function DetectLanguage(arg1:string):Promise<types.DetectResult>;
 
+export function ExportCache(arg1:cache.Filter):Promise<string>;
+
+export function ExportGlossary():Promise<string>;
+
 export function GetAccessibilityPermission():Promise<boolean>;
 
 export function GetActiveProvider():Promise<types.Provider>;
 
+export function GetCacheSettings():Promise<config.CacheSettings>;
+
+export function GetCacheStats():Promise<cache.Report>;
+
+export function GetDebugLogPath():Promise<string>;
+
+export function GetDebugLogging():Promise<boolean>;
+
 export function GetDefaultLanguages():Promise<Record<string, string>>;
 
+export function GetFallbacks():Promise<Array<string>>;
+
+export function GetGlossary():Promise<Array<glossary.Entry>>;
+
+export function GetMemorySettings():Promise<config.MemorySettings>;
+
+export function GetPromptRules():Promise<Array<prompt.Rule>>;
+
+export function GetPromptTemplates():Promise<Array<prompt.Template>>;
+
+export function GetProviderTypes():Promise<Array<llm.TypeInfo>>;
+
 export function GetProviders():Promise<Array<types.Provider>>;
 
+export function GetTelemetrySettings():Promise<telemetry.Settings>;
+
+export function GetVerificationSettings():Promise<config.VerificationSettings>;
+
+export function ImportCache(arg1:cache.Filter,arg2:string):Promise<cache.ImportResult>;
+
+export function ImportGlossary():Promise<number>;
+
+export function ListModels(arg1:types.Provider):Promise<Array<string>>;
+
+export function PutGlossaryEntry(arg1:glossary.Entry):Promise<void>;
+
+export function RefineTranslation(arg1:string,arg2:string):Promise<types.TranslateResult>;
+
+export function RemoveGlossaryEntry(arg1:glossary.Entry):Promise<void>;
+
+export function RemovePromptTemplate(arg1:string):Promise<void>;
+
 export function RemoveProvider(arg1:string):Promise<void>;
 
+export function ResetCacheStats():Promise<void>;
+
+export function SavePromptTemplate(arg1:prompt.Template):Promise<void>;
+
+export function SearchCache(arg1:string):Promise<Array<cache.Record>>;
+
+export function SearchCachePrefix(arg1:string):Promise<Array<cache.Record>>;
+
+export function SetCacheSettings(arg1:config.CacheSettings):Promise<void>;
+
+export function SetDebugLogging(arg1:boolean):Promise<void>;
+
 export function SetDefaultLanguage(arg1:string,arg2:string):Promise<void>;
 
+export function SetFallbacks(arg1:Array<string>):Promise<void>;
+
+export function SetMemorySettings(arg1:config.MemorySettings):Promise<void>;
+
+export function SetPromptRules(arg1:Array<prompt.Rule>):Promise<void>;
+
 export function SetProviderActive(arg1:string):Promise<void>;
 
+export function SetTelemetrySettings(arg1:telemetry.Settings):Promise<void>;
+
+export function SetVerificationSettings(arg1:config.VerificationSettings):Promise<void>;
+
 export function TakeScreenshotAndOCR():Promise<string>;
 
 export function ToggleWindowVisibility():Promise<void>;
 
+export function TranslateCompare(arg1:types.TranslateRequest,arg2:Array<string>):Promise<Array<types.CompareResult>>;
+
 export function TranslateWithLLM(arg1:types.TranslateRequest):Promise<types.TranslateResult>;
 
 export function UpdateProvider(arg1:string,arg2:types.Provider):Promise<void>;
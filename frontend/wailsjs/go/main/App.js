@@ -6,10 +6,26 @@ export function AddProvider(arg1) {
   return window['go']['main']['App']['AddProvider'](arg1);
 }
 
+export function CancelTranslation(arg1) {
+  return window['go']['main']['App']['CancelTranslation'](arg1);
+}
+
+export function DeleteCacheEntry(arg1) {
+  return window['go']['main']['App']['DeleteCacheEntry'](arg1);
+}
+
 export function DetectLanguage(arg1) {
   return window['go']['main']['App']['DetectLanguage'](arg1);
 }
 
+export function ExportCache(arg1) {
+  return window['go']['main']['App']['ExportCache'](arg1);
+}
+
+export function ExportGlossary() {
+  return window['go']['main']['App']['ExportGlossary']();
+}
+
 export function GetAccessibilityPermission() {
   return window['go']['main']['App']['GetAccessibilityPermission']();
 }
@@ -18,26 +34,146 @@ export function GetActiveProvider() {
   return window['go']['main']['App']['GetActiveProvider']();
 }
 
+export function GetCacheSettings() {
+  return window['go']['main']['App']['GetCacheSettings']();
+}
+
+export function GetCacheStats() {
+  return window['go']['main']['App']['GetCacheStats']();
+}
+
+export function GetDebugLogPath() {
+  return window['go']['main']['App']['GetDebugLogPath']();
+}
+
+export function GetDebugLogging() {
+  return window['go']['main']['App']['GetDebugLogging']();
+}
+
 export function GetDefaultLanguages() {
   return window['go']['main']['App']['GetDefaultLanguages']();
 }
 
+export function GetFallbacks() {
+  return window['go']['main']['App']['GetFallbacks']();
+}
+
+export function GetGlossary() {
+  return window['go']['main']['App']['GetGlossary']();
+}
+
+export function GetMemorySettings() {
+  return window['go']['main']['App']['GetMemorySettings']();
+}
+
+export function GetPromptRules() {
+  return window['go']['main']['App']['GetPromptRules']();
+}
+
+export function GetPromptTemplates() {
+  return window['go']['main']['App']['GetPromptTemplates']();
+}
+
+export function GetProviderTypes() {
+  return window['go']['main']['App']['GetProviderTypes']();
+}
+
 export function GetProviders() {
   return window['go']['main']['App']['GetProviders']();
 }
 
+export function GetTelemetrySettings() {
+  return window['go']['main']['App']['GetTelemetrySettings']();
+}
+
+export function GetVerificationSettings() {
+  return window['go']['main']['App']['GetVerificationSettings']();
+}
+
+export function ImportCache(arg1, arg2) {
+  return window['go']['main']['App']['ImportCache'](arg1, arg2);
+}
+
+export function ImportGlossary() {
+  return window['go']['main']['App']['ImportGlossary']();
+}
+
+export function ListModels(arg1) {
+  return window['go']['main']['App']['ListModels'](arg1);
+}
+
+export function PutGlossaryEntry(arg1) {
+  return window['go']['main']['App']['PutGlossaryEntry'](arg1);
+}
+
+export function RefineTranslation(arg1, arg2) {
+  return window['go']['main']['App']['RefineTranslation'](arg1, arg2);
+}
+
+export function RemoveGlossaryEntry(arg1) {
+  return window['go']['main']['App']['RemoveGlossaryEntry'](arg1);
+}
+
+export function RemovePromptTemplate(arg1) {
+  return window['go']['main']['App']['RemovePromptTemplate'](arg1);
+}
+
 export function RemoveProvider(arg1) {
   return window['go']['main']['App']['RemoveProvider'](arg1);
 }
 
+export function ResetCacheStats() {
+  return window['go']['main']['App']['ResetCacheStats']();
+}
+
+export function SavePromptTemplate(arg1) {
+  return window['go']['main']['App']['SavePromptTemplate'](arg1);
+}
+
+export function SearchCache(arg1) {
+  return window['go']['main']['App']['SearchCache'](arg1);
+}
+
+export function SearchCachePrefix(arg1) {
+  return window['go']['main']['App']['SearchCachePrefix'](arg1);
+}
+
+export function SetCacheSettings(arg1) {
+  return window['go']['main']['App']['SetCacheSettings'](arg1);
+}
+
+export function SetDebugLogging(arg1) {
+  return window['go']['main']['App']['SetDebugLogging'](arg1);
+}
+
 export function SetDefaultLanguage(arg1, arg2) {
   return window['go']['main']['App']['SetDefaultLanguage'](arg1, arg2);
 }
 
+export function SetFallbacks(arg1) {
+  return window['go']['main']['App']['SetFallbacks'](arg1);
+}
+
+export function SetMemorySettings(arg1) {
+  return window['go']['main']['App']['SetMemorySettings'](arg1);
+}
+
+export function SetPromptRules(arg1) {
+  return window['go']['main']['App']['SetPromptRules'](arg1);
+}
+
 export function SetProviderActive(arg1) {
   return window['go']['main']['App']['SetProviderActive'](arg1);
 }
 
+export function SetTelemetrySettings(arg1) {
+  return window['go']['main']['App']['SetTelemetrySettings'](arg1);
+}
+
+export function SetVerificationSettings(arg1) {
+  return window['go']['main']['App']['SetVerificationSettings'](arg1);
+}
+
 export function TakeScreenshotAndOCR() {
   return window['go']['main']['App']['TakeScreenshotAndOCR']();
 }
@@ -46,6 +182,10 @@ export function ToggleWindowVisibility() {
   return window['go']['main']['App']['ToggleWindowVisibility']();
 }
 
+export function TranslateCompare(arg1, arg2) {
+  return window['go']['main']['App']['TranslateCompare'](arg1, arg2);
+}
+
 export function TranslateWithLLM(arg1) {
   return window['go']['main']['App']['TranslateWithLLM'](arg1);
 }
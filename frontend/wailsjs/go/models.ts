@@ -1,5 +1,437 @@
+export namespace cache {
+	
+	export class Filter {
+	    source_lang?: string;
+	    target_lang?: string;
+	    provider?: string;
+	    since?: any;
+	    until?: any;
+	
+	    static createFrom(source: any = {}) {
+	        return new Filter(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.source_lang = source["source_lang"];
+	        this.target_lang = source["target_lang"];
+	        this.provider = source["provider"];
+	        this.since = this.convertValues(source["since"], null);
+	        this.until = this.convertValues(source["until"], null);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class GroupStats {
+	    provider: string;
+	    model: string;
+	    source_lang: string;
+	    target_lang: string;
+	    hits: number;
+	    misses: number;
+	    prompt_tokens_saved: number;
+	    completion_tokens_saved: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new GroupStats(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.provider = source["provider"];
+	        this.model = source["model"];
+	        this.source_lang = source["source_lang"];
+	        this.target_lang = source["target_lang"];
+	        this.hits = source["hits"];
+	        this.misses = source["misses"];
+	        this.prompt_tokens_saved = source["prompt_tokens_saved"];
+	        this.completion_tokens_saved = source["completion_tokens_saved"];
+	    }
+	}
+	export class ImportResult {
+	    imported: number;
+	    skipped: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new ImportResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.imported = source["imported"];
+	        this.skipped = source["skipped"];
+	    }
+	}
+	export class Record {
+	    key: string;
+	    text: string;
+	    reasoning?: string;
+	    usage: Usage;
+	    created_at: any;
+	    source_text?: string;
+	    source_lang?: string;
+	    target_lang?: string;
+	    provider?: string;
+	    model?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new Record(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.key = source["key"];
+	        this.text = source["text"];
+	        this.reasoning = source["reasoning"];
+	        this.usage = this.convertValues(source["usage"], Usage);
+	        this.created_at = this.convertValues(source["created_at"], null);
+	        this.source_text = source["source_text"];
+	        this.source_lang = source["source_lang"];
+	        this.target_lang = source["target_lang"];
+	        this.provider = source["provider"];
+	        this.model = source["model"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class Report {
+	    since: any;
+	    total: Stats;
+	    groups: Array<GroupStats>;
+	
+	    static createFrom(source: any = {}) {
+	        return new Report(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.since = this.convertValues(source["since"], null);
+	        this.total = this.convertValues(source["total"], Stats);
+	        this.groups = this.convertValues(source["groups"], GroupStats);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class Stats {
+	    hits: number;
+	    misses: number;
+	    prompt_tokens_saved: number;
+	    completion_tokens_saved: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new Stats(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.hits = source["hits"];
+	        this.misses = source["misses"];
+	        this.prompt_tokens_saved = source["prompt_tokens_saved"];
+	        this.completion_tokens_saved = source["completion_tokens_saved"];
+	    }
+	}
+	export class Usage {
+	    prompt_tokens: number;
+	    completion_tokens: number;
+	    total_tokens: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new Usage(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.prompt_tokens = source["prompt_tokens"];
+	        this.completion_tokens = source["completion_tokens"];
+	        this.total_tokens = source["total_tokens"];
+	    }
+	}
+
+}
+
+export namespace config {
+	
+	export class CacheSettings {
+	    ttl?: number;
+	    max_entries?: number;
+	    max_size_mb?: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new CacheSettings(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.ttl = source["ttl"];
+	        this.max_entries = source["max_entries"];
+	        this.max_size_mb = source["max_size_mb"];
+	    }
+	}
+	export class MemorySettings {
+	    disabled?: boolean;
+	    examples?: number;
+	    min_score?: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new MemorySettings(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.disabled = source["disabled"];
+	        this.examples = source["examples"];
+	        this.min_score = source["min_score"];
+	    }
+	}
+	export class VerificationSettings {
+	    back_translator?: string;
+	    judge?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new VerificationSettings(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.back_translator = source["back_translator"];
+	        this.judge = source["judge"];
+	    }
+	}
+
+}
+
+export namespace glossary {
+	
+	export class Entry {
+	    source: string;
+	    target?: string;
+	    source_lang?: string;
+	    target_lang?: string;
+	    do_not_translate?: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new Entry(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.source = source["source"];
+	        this.target = source["target"];
+	        this.source_lang = source["source_lang"];
+	        this.target_lang = source["target_lang"];
+	        this.do_not_translate = source["do_not_translate"];
+	    }
+	}
+
+}
+
+export namespace llm {
+	
+	export class Capabilities {
+	    streaming: boolean;
+	    listModels: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new Capabilities(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.streaming = source["streaming"];
+	        this.listModels = source["listModels"];
+	    }
+	}
+	export class TypeInfo {
+	    type: string;
+	    capabilities: Capabilities;
+	
+	    static createFrom(source: any = {}) {
+	        return new TypeInfo(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.type = source["type"];
+	        this.capabilities = this.convertValues(source["capabilities"], Capabilities);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+
+}
+
+export namespace prompt {
+	
+	export class Rule {
+	    source_lang?: string;
+	    target_lang?: string;
+	    template: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new Rule(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.source_lang = source["source_lang"];
+	        this.target_lang = source["target_lang"];
+	        this.template = source["template"];
+	    }
+	}
+	export class Template {
+	    id: string;
+	    name: string;
+	    text: string;
+	    domain?: string;
+	    tone?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new Template(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.name = source["name"];
+	        this.text = source["text"];
+	        this.domain = source["domain"];
+	        this.tone = source["tone"];
+	    }
+	}
+
+}
+
+export namespace telemetry {
+	
+	export class Settings {
+	    exporter?: string;
+	    endpoint?: string;
+	    headers?: Record<string, string>;
+	    file?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new Settings(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.exporter = source["exporter"];
+	        this.endpoint = source["endpoint"];
+	        this.headers = source["headers"];
+	        this.file = source["file"];
+	    }
+	}
+
+}
+
 export namespace types {
 	
+	export class CompareResult {
+	    provider: string;
+	    model: string;
+	    text: string;
+	    reasoning?: string;
+	    usage: Usage;
+	    latencyMs: number;
+	    error?: TranslateError;
+	
+	    static createFrom(source: any = {}) {
+	        return new CompareResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.provider = source["provider"];
+	        this.model = source["model"];
+	        this.text = source["text"];
+	        this.reasoning = source["reasoning"];
+	        this.usage = this.convertValues(source["usage"], Usage);
+	        this.latencyMs = source["latencyMs"];
+	        this.error = this.convertValues(source["error"], TranslateError);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
 	export class DetectResult {
 	    code: string;
 	    name: string;
@@ -16,6 +448,38 @@ export namespace types {
 	        this.defaultTarget = source["defaultTarget"];
 	    }
 	}
+	export class GlossaryIssue {
+	    source: string;
+	    expected: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new GlossaryIssue(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.source = source["source"];
+	        this.expected = source["expected"];
+	    }
+	}
+	export class MemoryMatch {
+	    source: string;
+	    target: string;
+	    score: number;
+	    provider?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new MemoryMatch(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.source = source["source"];
+	        this.target = source["target"];
+	        this.score = source["score"];
+	        this.provider = source["provider"];
+	    }
+	}
 	export class Provider {
 	    name: string;
 	    type: string;
@@ -27,6 +491,23 @@ export namespace types {
 	    temperature?: number;
 	    active: boolean;
 	    disable_thinking?: boolean;
+	    reasoning?: string;
+	    num_ctx?: number;
+	    keep_alive?: string;
+	    endpoint?: string;
+	    deployment?: string;
+	    api_version?: string;
+	    screenshot_mode?: string;
+	    prompt_template?: string;
+	    cache_ttl?: number;
+	    proxy?: string;
+	    ca_cert_file?: string;
+	    client_cert_file?: string;
+	    client_key_file?: string;
+	    request_timeout?: number;
+	    timeout?: number;
+	    headers?: Record<string, string>;
+	    extra_body?: Record<string, any>;
 	
 	    static createFrom(source: any = {}) {
 	        return new Provider(source);
@@ -44,12 +525,68 @@ export namespace types {
 	        this.temperature = source["temperature"];
 	        this.active = source["active"];
 	        this.disable_thinking = source["disable_thinking"];
+	        this.reasoning = source["reasoning"];
+	        this.num_ctx = source["num_ctx"];
+	        this.keep_alive = source["keep_alive"];
+	        this.endpoint = source["endpoint"];
+	        this.deployment = source["deployment"];
+	        this.api_version = source["api_version"];
+	        this.screenshot_mode = source["screenshot_mode"];
+	        this.prompt_template = source["prompt_template"];
+	        this.cache_ttl = source["cache_ttl"];
+	        this.proxy = source["proxy"];
+	        this.ca_cert_file = source["ca_cert_file"];
+	        this.client_cert_file = source["client_cert_file"];
+	        this.client_key_file = source["client_key_file"];
+	        this.request_timeout = source["request_timeout"];
+	        this.timeout = source["timeout"];
+	        this.headers = source["headers"];
+	        this.extra_body = source["extra_body"];
+	    }
+	}
+	export class QualityIssue {
+	    category: string;
+	    severity: string;
+	    span: string;
+	    explanation?: string;
+	    suggestion?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new QualityIssue(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.category = source["category"];
+	        this.severity = source["severity"];
+	        this.span = source["span"];
+	        this.explanation = source["explanation"];
+	        this.suggestion = source["suggestion"];
+	    }
+	}
+	export class TranslateError {
+	    code: string;
+	    message: string;
+	    retryAfterMs?: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new TranslateError(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.code = source["code"];
+	        this.message = source["message"];
+	        this.retryAfterMs = source["retryAfterMs"];
 	    }
 	}
 	export class TranslateRequest {
+	    id?: string;
 	    text: string;
 	    sourceLang: string;
 	    targetLang: string;
+	    image?: string;
+	    verify?: boolean;
 	
 	    static createFrom(source: any = {}) {
 	        return new TranslateRequest(source);
@@ -57,9 +594,12 @@ export namespace types {
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
 	        this.text = source["text"];
 	        this.sourceLang = source["sourceLang"];
 	        this.targetLang = source["targetLang"];
+	        this.image = source["image"];
+	        this.verify = source["verify"];
 	    }
 	}
 	export class Usage {
@@ -80,9 +620,55 @@ export namespace types {
 	        this.cacheHit = source["cacheHit"];
 	    }
 	}
+	export class Verification {
+	    backTranslation?: string;
+	    score: number;
+	    errors?: QualityIssue[];
+	    backTranslator?: string;
+	    judge?: string;
+	    error?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new Verification(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.backTranslation = source["backTranslation"];
+	        this.score = source["score"];
+	        this.errors = this.convertValues(source["errors"], QualityIssue);
+	        this.backTranslator = source["backTranslator"];
+	        this.judge = source["judge"];
+	        this.error = source["error"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
 	export class TranslateResult {
 	    text: string;
+	    reasoning?: string;
 	    usage: Usage;
+	    provider: string;
+	    glossaryIssues?: GlossaryIssue[];
+	    memoryMatches?: MemoryMatch[];
+	    sessionId?: string;
+	    verification?: Verification;
 	
 	    static createFrom(source: any = {}) {
 	        return new TranslateResult(source);
@@ -91,7 +677,13 @@ export namespace types {
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
 	        this.text = source["text"];
+	        this.reasoning = source["reasoning"];
 	        this.usage = this.convertValues(source["usage"], Usage);
+	        this.provider = source["provider"];
+	        this.glossaryIssues = this.convertValues(source["glossaryIssues"], GlossaryIssue);
+	        this.memoryMatches = this.convertValues(source["memoryMatches"], MemoryMatch);
+	        this.sessionId = source["sessionId"];
+	        this.verification = this.convertValues(source["verification"], Verification);
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
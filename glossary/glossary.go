@@ -0,0 +1,268 @@
+// Package glossary stores terminology that translations must respect.
+package glossary
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Entry is a term and the translation it must always get.
+// Empty languages match any language.
+type Entry struct {
+	Source         string `json:"source"`
+	Target         string `json:"target,omitempty"`
+	SourceLang     string `json:"source_lang,omitempty"`
+	TargetLang     string `json:"target_lang,omitempty"`
+	DoNotTranslate bool   `json:"do_not_translate,omitempty"` // keep Source as is, e.g. product names
+}
+
+// Translation returns the text the term must appear as in translations.
+func (e Entry) Translation() string {
+	if e.DoNotTranslate {
+		return e.Source
+	}
+	return e.Target
+}
+
+// Validate checks that the entry has a term and a translation for it.
+func (e Entry) Validate() error {
+	if strings.TrimSpace(e.Source) == "" {
+		return fmt.Errorf("glossary term required")
+	}
+	if !e.DoNotTranslate && strings.TrimSpace(e.Target) == "" {
+		return fmt.Errorf("translation required for %q", e.Source)
+	}
+	return nil
+}
+
+// sameKey reports whether two entries define the same term for the same
+// language pair, and so cannot both be stored.
+func (e Entry) sameKey(o Entry) bool {
+	return strings.EqualFold(e.Source, o.Source) &&
+		e.SourceLang == o.SourceLang &&
+		e.TargetLang == o.TargetLang
+}
+
+// Import and export formats.
+const (
+	FormatCSV  = "csv"
+	FormatJSON = "json"
+)
+
+// csvHeader is the header row of CSV exports; imports accept it optionally.
+var csvHeader = []string{"source", "target", "source_lang", "target_lang", "do_not_translate"}
+
+// Store is a glossary persisted as a JSON file.
+type Store struct {
+	path string
+
+	mu      sync.RWMutex
+	entries []Entry
+}
+
+// Open loads the glossary at path. A missing file is an empty glossary.
+func Open(path string) (*Store, error) {
+	s := &Store{path: path}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return s, nil
+		}
+		return nil, fmt.Errorf("read glossary: %w", err)
+	}
+	if err := json.Unmarshal(data, &s.entries); err != nil {
+		return nil, fmt.Errorf("unmarshal glossary: %w", err)
+	}
+	return s, nil
+}
+
+// Entries returns a copy of all entries.
+func (s *Store) Entries() []Entry {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return slices.Clone(s.entries)
+}
+
+// Put adds the entry, or replaces the one for the same term and language pair.
+func (s *Store) Put(e Entry) error {
+	if err := e.Validate(); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.update(put(slices.Clone(s.entries), e))
+}
+
+// Remove deletes the entry for the same term and language pair as e.
+func (s *Store) Remove(e Entry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	next := slices.DeleteFunc(slices.Clone(s.entries), e.sameKey)
+	if len(next) == len(s.entries) {
+		return fmt.Errorf("glossary entry not found: %s", e.Source)
+	}
+	return s.update(next)
+}
+
+// Import reads entries in the given format and merges them into the
+// glossary, replacing existing entries for the same term and language pair.
+// It returns the number of entries read.
+func (s *Store) Import(r io.Reader, format string) (int, error) {
+	var entries []Entry
+	var err error
+	switch format {
+	case FormatCSV:
+		entries, err = readCSV(r)
+	case FormatJSON:
+		err = json.NewDecoder(r).Decode(&entries)
+	default:
+		return 0, fmt.Errorf("unsupported glossary format: %s", format)
+	}
+	if err != nil {
+		return 0, fmt.Errorf("read glossary: %w", err)
+	}
+
+	for i, e := range entries {
+		if err := e.Validate(); err != nil {
+			return 0, fmt.Errorf("entry %d: %w", i+1, err)
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	next := slices.Clone(s.entries)
+	for _, e := range entries {
+		next = put(next, e)
+	}
+	if err := s.update(next); err != nil {
+		return 0, err
+	}
+	return len(entries), nil
+}
+
+// Export writes all entries in the given format.
+func (s *Store) Export(w io.Writer, format string) error {
+	entries := s.Entries()
+	switch format {
+	case FormatCSV:
+		return writeCSV(w, entries)
+	case FormatJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(entries)
+	default:
+		return fmt.Errorf("unsupported glossary format: %s", format)
+	}
+}
+
+// FormatOf returns the format implied by a file name's extension.
+func FormatOf(name string) (string, error) {
+	switch ext := strings.ToLower(filepath.Ext(name)); ext {
+	case ".csv":
+		return FormatCSV, nil
+	case ".json":
+		return FormatJSON, nil
+	default:
+		return "", fmt.Errorf("unsupported glossary file type: %s", ext)
+	}
+}
+
+// put adds e to entries, or replaces the entry for the same term and
+// language pair, and returns the result.
+func put(entries []Entry, e Entry) []Entry {
+	if idx := slices.IndexFunc(entries, e.sameKey); idx != -1 {
+		entries[idx] = e
+		return entries
+	}
+	return append(entries, e)
+}
+
+// update saves entries and, once they are saved, makes them the glossary's.
+// A failed save leaves the glossary as it was. s.mu must be held.
+func (s *Store) update(entries []Entry) error {
+	if err := s.save(entries); err != nil {
+		return err
+	}
+	s.entries = entries
+	return nil
+}
+
+func (s *Store) save(entries []Entry) error {
+	if err := os.MkdirAll(filepath.Dir(s.path), 0755); err != nil {
+		return fmt.Errorf("create glossary dir: %w", err)
+	}
+
+	data, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal glossary: %w", err)
+	}
+	if err := os.WriteFile(s.path, data, 0644); err != nil {
+		return fmt.Errorf("write glossary: %w", err)
+	}
+	return nil
+}
+
+func readCSV(r io.Reader) ([]Entry, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	cr.TrimLeadingSpace = true
+
+	records, err := cr.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(records) > 0 && strings.EqualFold(records[0][0], csvHeader[0]) {
+		records = records[1:]
+	}
+
+	entries := make([]Entry, 0, len(records))
+	for i, rec := range records {
+		if len(rec) < 2 {
+			return nil, fmt.Errorf("row %d: want at least source and target columns", i+1)
+		}
+		if len(rec) < len(csvHeader) {
+			rec = append(rec, make([]string, len(csvHeader)-len(rec))...)
+		}
+
+		e := Entry{Source: rec[0], Target: rec[1], SourceLang: rec[2], TargetLang: rec[3]}
+		if rec[4] != "" {
+			if e.DoNotTranslate, err = strconv.ParseBool(rec[4]); err != nil {
+				return nil, fmt.Errorf("row %d: invalid do_not_translate: %w", i+1, err)
+			}
+		}
+		entries = append(entries, e)
+	}
+	return entries, nil
+}
+
+func writeCSV(w io.Writer, entries []Entry) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(csvHeader); err != nil {
+		return err
+	}
+	for _, e := range entries {
+		rec := []string{e.Source, e.Target, e.SourceLang, e.TargetLang, ""}
+		if e.DoNotTranslate {
+			rec[4] = "true"
+		}
+		if err := cw.Write(rec); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
@@ -0,0 +1,157 @@
+package glossary
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestContainsTerm(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		term string
+		want bool
+	}{
+		{"whole word", "Open the Dashboard now", "dashboard", true},
+		{"partial word", "concatenate", "cat", false},
+		{"later whole word", "concatenate the cat", "cat", true},
+		{"punctuation boundary", "(Transy)", "transy", true},
+		{"multi-word term", "use the API gateway here", "api gateway", true},
+		{"cjk term", "请打开控制台页面", "控制台", true},
+		{"latin term in cjk text", "使用GPU加速", "GPU", true},
+		{"absent", "hello world", "dashboard", false},
+		{"empty term", "hello", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := containsTerm(tt.text, tt.term); got != tt.want {
+				t.Errorf("containsTerm(%q, %q) = %v, want %v", tt.text, tt.term, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMatchAndCheck(t *testing.T) {
+	s := newTestStore(t)
+	for _, e := range []Entry{
+		{Source: "dashboard", Target: "仪表盘"},
+		{Source: "dashboard", Target: "控制面板", SourceLang: "en", TargetLang: "zh"},
+		{Source: "Transy", DoNotTranslate: true},
+		{Source: "token", Target: "トークン", TargetLang: "ja"},
+	} {
+		if err := s.Put(e); err != nil {
+			t.Fatalf("put %q: %v", e.Source, err)
+		}
+	}
+
+	matched := s.Match("Open the Transy dashboard to see token usage", "en", "zh")
+	if len(matched) != 2 {
+		t.Fatalf("matched %d entries, want 2: %+v", len(matched), matched)
+	}
+	if matched[0].Target != "控制面板" {
+		t.Errorf("pair-specific entry should win, got %q", matched[0].Target)
+	}
+
+	missing := Check("打开 Transy 仪表盘查看用量", matched)
+	if len(missing) != 1 || missing[0].Source != "dashboard" {
+		t.Errorf("missing = %+v, want only dashboard", missing)
+	}
+	if missing := Check("打开 Transy 控制面板查看用量", matched); len(missing) != 0 {
+		t.Errorf("missing = %+v, want none", missing)
+	}
+}
+
+func TestImportExport(t *testing.T) {
+	s := newTestStore(t)
+
+	csvData := "source,target,source_lang,target_lang,do_not_translate\n" +
+		"dashboard,仪表盘,en,zh,\n" +
+		"Transy,,,,true\n" +
+		"token,令牌\n"
+	n, err := s.Import(strings.NewReader(csvData), FormatCSV)
+	if err != nil {
+		t.Fatalf("import: %v", err)
+	}
+	if n != 3 || len(s.Entries()) != 3 {
+		t.Fatalf("imported %d, stored %d, want 3", n, len(s.Entries()))
+	}
+
+	// Re-importing replaces entries for the same term and pair
+	if _, err := s.Import(strings.NewReader("Dashboard,控制面板,en,zh\n"), FormatCSV); err != nil {
+		t.Fatalf("re-import: %v", err)
+	}
+	if got := s.Entries(); len(got) != 3 || got[0].Target != "控制面板" {
+		t.Errorf("entries after re-import = %+v", got)
+	}
+
+	// Round trip through both formats into a reopened store
+	for _, format := range []string{FormatCSV, FormatJSON} {
+		var buf bytes.Buffer
+		if err := s.Export(&buf, format); err != nil {
+			t.Fatalf("export %s: %v", format, err)
+		}
+		other := newTestStore(t)
+		if _, err := other.Import(&buf, format); err != nil {
+			t.Fatalf("import %s: %v", format, err)
+		}
+		reopened, err := Open(other.path)
+		if err != nil {
+			t.Fatalf("reopen: %v", err)
+		}
+		if got, want := reopened.Entries(), s.Entries(); len(got) != len(want) || got[1] != want[1] {
+			t.Errorf("%s round trip = %+v, want %+v", format, got, want)
+		}
+	}
+
+	if _, err := s.Import(strings.NewReader("orphan,\n"), FormatCSV); err == nil {
+		t.Error("expected error for entry without translation")
+	}
+}
+
+func TestFailedSaveLeavesEntries(t *testing.T) {
+	s := newTestStore(t)
+	if err := s.Put(Entry{Source: "dashboard", Target: "仪表盘"}); err != nil {
+		t.Fatalf("put: %v", err)
+	}
+
+	// A file where the glossary's directory should be makes saving fail.
+	blocker := filepath.Join(t.TempDir(), "blocker")
+	if err := os.WriteFile(blocker, nil, 0644); err != nil {
+		t.Fatalf("write blocker: %v", err)
+	}
+	s.path = filepath.Join(blocker, "glossary.json")
+
+	changes := []struct {
+		name string
+		fn   func() error
+	}{
+		{"put", func() error { return s.Put(Entry{Source: "token", Target: "令牌"}) }},
+		{"replace", func() error { return s.Put(Entry{Source: "dashboard", Target: "控制面板"}) }},
+		{"remove", func() error { return s.Remove(Entry{Source: "dashboard"}) }},
+		{"import", func() error {
+			_, err := s.Import(strings.NewReader("dashboard,控制面板\ntoken,令牌\n"), FormatCSV)
+			return err
+		}},
+	}
+	for _, c := range changes {
+		if err := c.fn(); err == nil {
+			t.Errorf("%s: expected a save error", c.name)
+		}
+		if got := s.Entries(); len(got) != 1 || got[0].Target != "仪表盘" {
+			t.Errorf("%s: entries after a failed save = %+v", c.name, got)
+		}
+	}
+}
+
+func newTestStore(t *testing.T) *Store {
+	t.Helper()
+	s, err := Open(filepath.Join(t.TempDir(), "glossary.json"))
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	return s
+}
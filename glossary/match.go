@@ -0,0 +1,114 @@
+package glossary
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Match returns the entries for the language pair whose term occurs in text.
+// An entry for the exact pair takes precedence over a wildcard entry for the
+// same term.
+func (s *Store) Match(text, sourceLang, targetLang string) []Entry {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var matched []Entry
+	for _, e := range s.entries {
+		if !langMatches(e.SourceLang, sourceLang) || !langMatches(e.TargetLang, targetLang) {
+			continue
+		}
+		if !containsTerm(text, e.Source) {
+			continue
+		}
+		if i := indexTerm(matched, e.Source); i != -1 {
+			if specificity(e) > specificity(matched[i]) {
+				matched[i] = e
+			}
+			continue
+		}
+		matched = append(matched, e)
+	}
+	return matched
+}
+
+// Check returns the entries whose required translation is missing from the
+// translated text.
+func Check(translation string, entries []Entry) []Entry {
+	var missing []Entry
+	for _, e := range entries {
+		if !containsTerm(translation, e.Translation()) {
+			missing = append(missing, e)
+		}
+	}
+	return missing
+}
+
+func langMatches(want, got string) bool {
+	return want == "" || want == got
+}
+
+func specificity(e Entry) int {
+	n := 0
+	if e.SourceLang != "" {
+		n++
+	}
+	if e.TargetLang != "" {
+		n++
+	}
+	return n
+}
+
+func indexTerm(entries []Entry, term string) int {
+	for i, e := range entries {
+		if strings.EqualFold(e.Source, term) {
+			return i
+		}
+	}
+	return -1
+}
+
+// containsTerm reports whether term occurs in text, ignoring case. Terms in
+// scripts that separate words with spaces must match whole words, so "cat"
+// is not found in "concatenate"; CJK terms match anywhere.
+func containsTerm(text, term string) bool {
+	if term == "" {
+		return false
+	}
+	text, term = strings.ToLower(text), strings.ToLower(term)
+
+	first, _ := utf8.DecodeRuneInString(term)
+	last, _ := utf8.DecodeLastRuneInString(term)
+
+	for off := 0; ; {
+		i := strings.Index(text[off:], term)
+		if i == -1 {
+			return false
+		}
+		start, end := off+i, off+i+len(term)
+
+		before, _ := utf8.DecodeLastRuneInString(text[:start])
+		after, _ := utf8.DecodeRuneInString(text[end:])
+		if (start == 0 || !joins(first, before)) && (end == len(text) || !joins(last, after)) {
+			return true
+		}
+		off = start + utf8.RuneLen(first)
+	}
+}
+
+// joins reports whether the runes would be part of the same word, making a
+// term edge at r a partial-word match. Words in unspaced scripts have no
+// visible boundary, so they never join.
+func joins(r, neighbor rune) bool {
+	return isWordRune(r) && isWordRune(neighbor) && !isUnspaced(r) && !isUnspaced(neighbor)
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_'
+}
+
+// isUnspaced reports whether r belongs to a script written without spaces
+// between words.
+func isUnspaced(r rune) bool {
+	return unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul, unicode.Thai)
+}
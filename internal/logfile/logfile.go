@@ -0,0 +1,108 @@
+// Package logfile provides a size-rotated log file.
+package logfile
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// File is an append-only log file that is rotated once it would grow past
+// its maximum size: path is renamed to path.1, path.1 to path.2 and so on,
+// keeping a fixed number of backups. It is safe for concurrent use.
+type File struct {
+	path    string
+	maxSize int64
+	backups int
+
+	mu   sync.Mutex
+	f    *os.File // nil once closed
+	size int64
+}
+
+// Open opens or creates the log file at path, creating its directory if
+// needed. A write that would take the file past maxSize bytes rotates it
+// first; at most backups rotated files are kept.
+func Open(path string, maxSize int64, backups int) (*File, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("create log dir: %w", err)
+	}
+	l := &File{path: path, maxSize: maxSize, backups: backups}
+	if err := l.open(); err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+// Write appends p to the file, rotating it first if needed. A single write
+// is never split across files.
+func (l *File) Write(p []byte) (int, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.f == nil {
+		return 0, os.ErrClosed
+	}
+	if l.size > 0 && l.size+int64(len(p)) > l.maxSize {
+		if err := l.rotate(); err != nil {
+			return 0, err
+		}
+	}
+	n, err := l.f.Write(p)
+	l.size += int64(n)
+	return n, err
+}
+
+// Close closes the file.
+func (l *File) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.f == nil {
+		return nil
+	}
+	err := l.f.Close()
+	l.f = nil
+	return err
+}
+
+func (l *File) open() error {
+	// Owner-only: the log may hold request and response bodies.
+	f, err := os.OpenFile(l.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return fmt.Errorf("open log file: %w", err)
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return fmt.Errorf("stat log file: %w", err)
+	}
+	l.f, l.size = f, info.Size()
+	return nil
+}
+
+// rotate shifts the backups up by one, dropping the oldest, and starts a
+// new file.
+func (l *File) rotate() error {
+	if err := l.f.Close(); err != nil {
+		return fmt.Errorf("close log file: %w", err)
+	}
+	l.f = nil
+
+	if l.backups > 0 {
+		for i := l.backups - 1; i >= 1; i-- {
+			_ = os.Rename(l.backupPath(i), l.backupPath(i+1))
+		}
+		if err := os.Rename(l.path, l.backupPath(1)); err != nil {
+			return fmt.Errorf("rotate log file: %w", err)
+		}
+	} else if err := os.Remove(l.path); err != nil {
+		return fmt.Errorf("rotate log file: %w", err)
+	}
+	return l.open()
+}
+
+func (l *File) backupPath(i int) string {
+	return fmt.Sprintf("%s.%d", l.path, i)
+}
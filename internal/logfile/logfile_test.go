@@ -0,0 +1,72 @@
+package logfile
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRotation(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "logs", "debug.log")
+	l, err := Open(path, 10, 2)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	defer l.Close()
+
+	for _, line := range []string{"first\n", "second\n", "third\n", "fourth\n"} {
+		if _, err := l.Write([]byte(line)); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+	}
+
+	// Every line overflows a 10-byte file holding another, so each rotated
+	// out; the oldest fell off the end.
+	tests := []struct {
+		path string
+		want string
+	}{
+		{path, "fourth\n"},
+		{path + ".1", "third\n"},
+		{path + ".2", "second\n"},
+	}
+	for _, tt := range tests {
+		data, err := os.ReadFile(tt.path)
+		if err != nil {
+			t.Fatalf("read %s: %v", tt.path, err)
+		}
+		if string(data) != tt.want {
+			t.Errorf("%s = %q, want %q", filepath.Base(tt.path), data, tt.want)
+		}
+	}
+	if _, err := os.Stat(path + ".3"); !os.IsNotExist(err) {
+		t.Errorf("expected only 2 backups, stat .3: %v", err)
+	}
+}
+
+func TestAppendsToExisting(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "debug.log")
+	if err := os.WriteFile(path, []byte("old\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	l, err := Open(path, 1024, 1)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	if _, err := l.Write([]byte("new\n")); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if err := l.Close(); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+	if _, err := l.Write([]byte("late\n")); err == nil {
+		t.Error("write after close succeeded")
+	}
+
+	data, _ := os.ReadFile(path)
+	if !strings.HasPrefix(string(data), "old\n") || !strings.HasSuffix(string(data), "new\n") {
+		t.Errorf("file = %q", data)
+	}
+}
@@ -4,7 +4,7 @@ package types
 // Provider represents an LLM provider configuration.
 type Provider struct {
 	Name            string  `json:"name"`
-	Type            string  `json:"type"` // "openai", "openai-compatible", "gemini", "claude"
+	Type            string  `json:"type"` // "openai", "openai-compatible", "gemini", "claude", "ollama", "azure-openai"
 	BaseURL         string  `json:"base_url,omitempty"`
 	APIKey          string  `json:"api_key"`
 	Model           string  `json:"model"`
@@ -12,7 +12,28 @@ type Provider struct {
 	MaxTokens       int     `json:"max_tokens,omitempty"`
 	Temperature     float64 `json:"temperature,omitempty"`
 	Active          bool    `json:"active"`
-	DisableThinking bool    `json:"disable_thinking,omitempty"` // For Gemini: set thinkingBudget to 0
+	DisableThinking bool    `json:"disable_thinking,omitempty"` // For Gemini: think as little as the model allows; ignored when Reasoning is set
+	Reasoning       string  `json:"reasoning,omitempty"`        // "off", "low", "medium" or "high"; empty uses the model default
+	NumCtx          int     `json:"num_ctx,omitempty"`          // For Ollama: context window size
+	KeepAlive       string  `json:"keep_alive,omitempty"`       // For Ollama: how long the model stays loaded, e.g. "5m"
+	Endpoint        string  `json:"endpoint,omitempty"`         // For Azure OpenAI: resource endpoint, e.g. "https://{resource}.openai.azure.com"
+	Deployment      string  `json:"deployment,omitempty"`       // For Azure OpenAI: deployment name
+	APIVersion      string  `json:"api_version,omitempty"`      // For Azure OpenAI: api-version query parameter
+	ScreenshotMode  string  `json:"screenshot_mode,omitempty"`  // ScreenshotModeOCR (default) or ScreenshotModeVision
+	PromptTemplate  string  `json:"prompt_template,omitempty"`  // ID of the prompt template to use; empty uses the default
+	CacheTTL        int     `json:"cache_ttl,omitempty"`        // hours translations are cached; 0 uses the global setting, -1 keeps them until evicted
+
+	// Network settings, applied to every provider type.
+	Proxy          string `json:"proxy,omitempty"`            // http://, https:// or socks5:// proxy URL; empty uses the environment
+	CACertFile     string `json:"ca_cert_file,omitempty"`     // PEM bundle trusted in addition to the system roots
+	ClientCertFile string `json:"client_cert_file,omitempty"` // PEM client certificate for mutual TLS
+	ClientKeyFile  string `json:"client_key_file,omitempty"`  // PEM private key for ClientCertFile
+	RequestTimeout int    `json:"request_timeout,omitempty"`  // seconds to wait for each attempt's response headers; 0 means no limit
+	Timeout        int    `json:"timeout,omitempty"`          // seconds for the whole call including retries; 0 means no limit
+
+	// Request customization for gateways and parameters the backends don't model.
+	Headers   map[string]string `json:"headers,omitempty"`    // extra HTTP headers, e.g. "HTTP-Referer"; override built-in ones
+	ExtraBody map[string]any    `json:"extra_body,omitempty"` // merged into the JSON request body, e.g. {"top_p": 0.9}
 }
 
 // DefaultMaxTokens is the default max tokens if not specified.
@@ -21,11 +42,20 @@ const DefaultMaxTokens = 1000
 // DefaultTemperature is the default temperature if not specified.
 const DefaultTemperature = 0.3
 
+// Screenshot translation modes.
+const (
+	ScreenshotModeOCR    = "ocr"    // recognize text locally, then translate it
+	ScreenshotModeVision = "vision" // send the image itself to a vision-capable model
+)
+
 // TranslateRequest represents a translation request from the frontend.
 type TranslateRequest struct {
+	ID         string `json:"id,omitempty"` // Correlates streaming events and cancellation; generated if empty
 	Text       string `json:"text"`
 	SourceLang string `json:"sourceLang"`
 	TargetLang string `json:"targetLang"`
+	Image      string `json:"image,omitempty"`  // base64-encoded PNG; translated by a vision model instead of Text
+	Verify     bool   `json:"verify,omitempty"` // back-translate and score the result
 }
 
 // DetectResult represents the result of language detection.
@@ -45,6 +75,81 @@ type Usage struct {
 
 // TranslateResult represents the result of a translation request.
 type TranslateResult struct {
-	Text  string `json:"text"`
-	Usage Usage  `json:"usage"`
+	Text           string          `json:"text"`
+	Reasoning      string          `json:"reasoning,omitempty"` // the model's reasoning, kept out of Text
+	Usage          Usage           `json:"usage"`
+	Provider       string          `json:"provider"`                 // name of the provider that served the result
+	GlossaryIssues []GlossaryIssue `json:"glossaryIssues,omitempty"` // glossary terms not applied in Text
+	MemoryMatches  []MemoryMatch   `json:"memoryMatches,omitempty"`  // similar earlier translations, best first
+	SessionID      string          `json:"sessionId,omitempty"`      // pass to RefineTranslation to revise Text
+	Verification   *Verification   `json:"verification,omitempty"`   // set when the request asked for verification
+}
+
+// Verification is the quality estimate of a translation: its back-translation
+// into the source language and a judge model's MQM-style assessment.
+type Verification struct {
+	BackTranslation string         `json:"backTranslation,omitempty"`
+	Score           float64        `json:"score"` // 0-100; 100 means no errors were found
+	Errors          []QualityIssue `json:"errors,omitempty"`
+	BackTranslator  string         `json:"backTranslator,omitempty"` // provider names
+	Judge           string         `json:"judge,omitempty"`
+	Error           string         `json:"error,omitempty"` // why verification failed; the translation stands
+}
+
+// QualityIssue is an error the judge flagged in a translation.
+type QualityIssue struct {
+	Category    string `json:"category"` // MQM dimension and subtype, e.g. "accuracy/mistranslation"
+	Severity    string `json:"severity"` // minor, major or critical
+	Span        string `json:"span"`     // the offending text in the translation
+	Explanation string `json:"explanation,omitempty"`
+	Suggestion  string `json:"suggestion,omitempty"`
+}
+
+// MemoryMatch is an earlier translation of text similar to the request's.
+type MemoryMatch struct {
+	Source   string  `json:"source"`
+	Target   string  `json:"target"`
+	Score    float64 `json:"score"` // similarity in (0, 1]; 1 is an exact match
+	Provider string  `json:"provider,omitempty"`
+}
+
+// GlossaryIssue flags a glossary term whose required translation is
+// missing from the output.
+type GlossaryIssue struct {
+	Source   string `json:"source"`
+	Expected string `json:"expected"`
+}
+
+// CompareResult is one provider's outcome in a side-by-side comparison.
+type CompareResult struct {
+	Provider  string          `json:"provider"`
+	Model     string          `json:"model"`
+	Text      string          `json:"text"`
+	Reasoning string          `json:"reasoning,omitempty"`
+	Usage     Usage           `json:"usage"`
+	LatencyMs int64           `json:"latencyMs"`
+	Error     *TranslateError `json:"error,omitempty"`
+}
+
+// TranslateDelta is emitted to the frontend for each streamed chunk of a translation.
+type TranslateDelta struct {
+	ID    string `json:"id"`
+	Delta string `json:"delta"`
+}
+
+// TranslateDone is emitted to the frontend when a translation has finished.
+type TranslateDone struct {
+	ID        string `json:"id"`
+	Text      string `json:"text"`
+	Reasoning string `json:"reasoning,omitempty"`
+	Usage     Usage  `json:"usage"`
+	Provider  string `json:"provider"`
+}
+
+// TranslateError is the structured error passed to the frontend when a
+// translation fails, so it can show a message appropriate to the cause.
+type TranslateError struct {
+	Code         string `json:"code"` // llm.ErrorCode, e.g. "auth" or "rate_limited"
+	Message      string `json:"message"`
+	RetryAfterMs int64  `json:"retryAfterMs,omitempty"`
 }
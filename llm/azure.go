@@ -0,0 +1,61 @@
+package llm
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"go.aimuz.me/transy/internal/types"
+)
+
+// defaultAzureAPIVersion is used when the provider does not set one.
+// https://learn.microsoft.com/azure/ai-services/openai/reference
+const defaultAzureAPIVersion = "2024-10-21"
+
+// Azure OpenAI speaks the OpenAI chat completions protocol, so it shares
+// openaiProvider and differs only in URL shape and authentication.
+func init() {
+	Register(Registration{
+		Type:         "azure-openai",
+		Capabilities: Capabilities{Streaming: true},
+		Validate:     validateAzure,
+		New: func(p *types.Provider, hc *http.Client) Provider {
+			return &openaiProvider{provider: p, http: hc}
+		},
+	})
+}
+
+// validateAzure checks the resource endpoint and deployment. The model is
+// optional because the deployment already determines it.
+func validateAzure(p *types.Provider) error {
+	if p.APIKey == "" {
+		return fmt.Errorf("api key required")
+	}
+	if p.Endpoint == "" {
+		return fmt.Errorf("endpoint required for azure-openai")
+	}
+	u, err := url.Parse(p.Endpoint)
+	if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+		return fmt.Errorf("invalid endpoint %q: want e.g. https://{resource}.openai.azure.com", p.Endpoint)
+	}
+	if p.Deployment == "" {
+		return fmt.Errorf("deployment required for azure-openai")
+	}
+	if strings.ContainsAny(p.Deployment, "/?#") {
+		return fmt.Errorf("invalid deployment name %q", p.Deployment)
+	}
+	return nil
+}
+
+// azureURL returns the chat completions URL for the provider's deployment.
+func azureURL(p *types.Provider) string {
+	version := p.APIVersion
+	if version == "" {
+		version = defaultAzureAPIVersion
+	}
+	return fmt.Sprintf("%s/openai/deployments/%s/chat/completions?api-version=%s",
+		strings.TrimRight(p.Endpoint, "/"),
+		url.PathEscape(p.Deployment),
+		url.QueryEscape(version))
+}
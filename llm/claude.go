@@ -2,10 +2,12 @@ package llm
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
+	"strings"
 
 	"go.aimuz.me/transy/internal/types"
 )
@@ -13,9 +15,40 @@ import (
 // https://api.anthropic.com/v1/messages
 const defaultClaudeBaseURL = "https://api.anthropic.com/v1/messages"
 
+// claudeProvider implements Provider for the Anthropic Messages API.
+type claudeProvider struct {
+	provider *types.Provider
+	http     *http.Client
+}
+
+func init() {
+	Register(Registration{
+		Type:         "claude",
+		Capabilities: Capabilities{Streaming: true},
+		Validate:     requireKeyAndModel,
+		New: func(p *types.Provider, hc *http.Client) Provider {
+			return &claudeProvider{provider: p, http: hc}
+		},
+	})
+}
+
+// claudeMessage is a message whose content is a string, or an array of
+// content blocks when images are attached.
 type claudeMessage struct {
 	Role    string `json:"role"`
-	Content string `json:"content"`
+	Content any    `json:"content"`
+}
+
+type claudeContentBlock struct {
+	Type   string             `json:"type"` // "text" or "image"
+	Text   string             `json:"text,omitempty"`
+	Source *claudeImageSource `json:"source,omitempty"`
+}
+
+type claudeImageSource struct {
+	Type      string `json:"type"` // "base64"
+	MediaType string `json:"media_type"`
+	Data      string `json:"data"`
 }
 
 type claudeRequest struct {
@@ -23,23 +56,197 @@ type claudeRequest struct {
 	Messages  []claudeMessage `json:"messages"`
 	System    string          `json:"system,omitempty"`
 	MaxTokens int             `json:"max_tokens,omitempty"`
+	Stream    bool            `json:"stream,omitempty"`
+	Thinking  *claudeThinking `json:"thinking,omitempty"`
+}
+
+// claudeThinking enables extended thinking.
+// https://docs.anthropic.com/en/docs/build-with-claude/extended-thinking
+type claudeThinking struct {
+	Type         string `json:"type"` // "enabled"
+	BudgetTokens int    `json:"budget_tokens"`
+}
+
+type claudeUsage struct {
+	InputTokens  int `json:"input_tokens"`
+	OutputTokens int `json:"output_tokens"`
+}
+
+type claudeError struct {
+	Type    string `json:"type"`
+	Message string `json:"message"`
 }
 
 type claudeResponse struct {
 	Content []struct {
-		Text string `json:"text"`
+		Type     string `json:"type"` // "text", "thinking" or "redacted_thinking"
+		Text     string `json:"text"`
+		Thinking string `json:"thinking"`
 	} `json:"content"`
-	Usage *struct {
-		InputTokens  int `json:"input_tokens"`
-		OutputTokens int `json:"output_tokens"`
-	} `json:"usage,omitempty"`
-	Error *struct {
-		Type    string `json:"type"`
-		Message string `json:"message"`
-	} `json:"error,omitempty"`
+	StopReason string       `json:"stop_reason"`
+	Usage      *claudeUsage `json:"usage,omitempty"`
+	Error      *claudeError `json:"error,omitempty"`
+}
+
+// claudeStreamEvent covers the event payloads of the Messages streaming API.
+type claudeStreamEvent struct {
+	Type    string `json:"type"`
+	Message *struct {
+		Usage *claudeUsage `json:"usage,omitempty"`
+	} `json:"message,omitempty"`
+	Delta *struct {
+		Type       string `json:"type"`
+		Text       string `json:"text"`
+		Thinking   string `json:"thinking"`
+		StopReason string `json:"stop_reason"`
+	} `json:"delta,omitempty"`
+	Usage *claudeUsage `json:"usage,omitempty"`
+	Error *claudeError `json:"error,omitempty"`
+}
+
+func (c *claudeProvider) Complete(ctx context.Context, messages []Message) (Response, error) {
+	resp, err := c.doClaude(ctx, c.newClaudeRequest(messages))
+	if err != nil {
+		return Response{}, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return Response{}, fmt.Errorf("read response: %w", err)
+	}
+
+	var claudeResp claudeResponse
+	if err := json.Unmarshal(body, &claudeResp); err != nil {
+		return Response{}, fmt.Errorf("unmarshal response: %w", err)
+	}
+
+	if claudeResp.Error != nil {
+		return Response{}, claudeResp.Error.toError()
+	}
+
+	if claudeResp.StopReason == "refusal" {
+		return Response{}, blockedError("refusal")
+	}
+
+	if len(claudeResp.Content) == 0 {
+		return Response{}, fmt.Errorf("no content returned")
+	}
+
+	// With extended thinking the text block follows one or more thinking blocks.
+	var text, reasoning strings.Builder
+	for _, block := range claudeResp.Content {
+		switch block.Type {
+		case "thinking":
+			reasoning.WriteString(block.Thinking)
+		case "text", "":
+			text.WriteString(block.Text)
+		}
+	}
+
+	var usage types.Usage
+	if claudeResp.Usage != nil {
+		usage = claudeResp.Usage.toUsage()
+	}
+
+	return Response{Text: text.String(), Reasoning: reasoning.String(), Usage: usage}, nil
+}
+
+func (c *claudeProvider) Stream(ctx context.Context, messages []Message, fn StreamFunc) (Response, error) {
+	reqBody := c.newClaudeRequest(messages)
+	reqBody.Stream = true
+
+	resp, err := c.doClaude(ctx, reqBody)
+	if err != nil {
+		return Response{}, err
+	}
+	defer resp.Body.Close()
+
+	var text, reasoning strings.Builder
+	var usage claudeUsage
+	done := false // whether message_stop was seen
+
+	err = readSSE(resp.Body, func(ev sseEvent) error {
+		var event claudeStreamEvent
+		if err := json.Unmarshal([]byte(ev.Data), &event); err != nil {
+			return fmt.Errorf("unmarshal event: %w", err)
+		}
+
+		switch event.Type {
+		case "message_start":
+			if event.Message != nil && event.Message.Usage != nil {
+				usage.InputTokens = event.Message.Usage.InputTokens
+			}
+		case "content_block_delta":
+			if event.Delta == nil {
+				break
+			}
+			switch event.Delta.Type {
+			case "text_delta":
+				if event.Delta.Text != "" {
+					text.WriteString(event.Delta.Text)
+					fn(event.Delta.Text)
+				}
+			case "thinking_delta":
+				reasoning.WriteString(event.Delta.Thinking)
+			}
+		case "message_delta":
+			if event.Usage != nil {
+				usage.OutputTokens = event.Usage.OutputTokens
+			}
+			if event.Delta != nil && event.Delta.StopReason == "refusal" {
+				return blockedError("refusal")
+			}
+		case "message_stop":
+			done = true
+			return errStopStream
+		case "error":
+			if event.Error != nil {
+				return event.Error.toError()
+			}
+			return &Error{Code: CodeUnknown, Message: "unknown stream error"}
+		}
+		return nil
+	})
+	if err != nil {
+		return Response{}, err
+	}
+	if !done {
+		return Response{}, truncatedError()
+	}
+
+	return Response{Text: text.String(), Reasoning: reasoning.String(), Usage: usage.toUsage()}, nil
+}
+
+// toError converts an error object, e.g. from an in-band stream event, into an *Error.
+// See https://docs.anthropic.com/en/api/errors for the error types.
+func (e *claudeError) toError() *Error {
+	code := CodeUnknown
+	switch e.Type {
+	case "authentication_error", "permission_error":
+		code = CodeAuth
+	case "rate_limit_error":
+		code = CodeRateLimited
+	case "overloaded_error", "api_error", "timeout_error":
+		code = CodeTransient
+	case "invalid_request_error", "not_found_error", "request_too_large":
+		code = CodeBadRequest
+		if isQuotaMessage(strings.ToLower(e.Message)) {
+			code = CodeQuotaExhausted
+		}
+	}
+	return &Error{Code: code, Message: e.Message}
+}
+
+func (u claudeUsage) toUsage() types.Usage {
+	return types.Usage{
+		PromptTokens:     u.InputTokens,
+		CompletionTokens: u.OutputTokens,
+		TotalTokens:      u.InputTokens + u.OutputTokens,
+	}
 }
 
-func (c *Client) completeClaude(messages []Message) (string, types.Usage, error) {
+func (c *claudeProvider) newClaudeRequest(messages []Message) claudeRequest {
 	var claudeMsgs []claudeMessage
 	var systemPrompt string
 
@@ -48,10 +255,24 @@ func (c *Client) completeClaude(messages []Message) (string, types.Usage, error)
 			systemPrompt += msg.Content
 			continue
 		}
-		claudeMsgs = append(claudeMsgs, claudeMessage{
-			Role:    msg.Role,
-			Content: msg.Content,
-		})
+		if len(msg.Images) == 0 {
+			claudeMsgs = append(claudeMsgs, claudeMessage{
+				Role:    msg.Role,
+				Content: msg.Content,
+			})
+			continue
+		}
+
+		// Anthropic recommends placing images before the text that refers to them.
+		blocks := make([]claudeContentBlock, 0, len(msg.Images)+1)
+		for _, img := range msg.Images {
+			blocks = append(blocks, claudeContentBlock{
+				Type:   "image",
+				Source: &claudeImageSource{Type: "base64", MediaType: img.MIMEType, Data: img.base64()},
+			})
+		}
+		blocks = append(blocks, claudeContentBlock{Type: "text", Text: msg.Content})
+		claudeMsgs = append(claudeMsgs, claudeMessage{Role: msg.Role, Content: blocks})
 	}
 
 	reqBody := claudeRequest{
@@ -65,57 +286,57 @@ func (c *Client) completeClaude(messages []Message) (string, types.Usage, error)
 		reqBody.MaxTokens = 1024 // Claude requires max_tokens
 	}
 
-	jsonBody, err := json.Marshal(reqBody)
-	if err != nil {
-		return "", types.Usage{}, fmt.Errorf("marshal request: %w", err)
+	// max_tokens must exceed the thinking budget, so the budget is added
+	// on top of the tokens reserved for the translation itself.
+	if budget := reasoningBudget(c.provider.Reasoning); budget > 0 {
+		reqBody.Thinking = &claudeThinking{Type: "enabled", BudgetTokens: budget}
+		reqBody.MaxTokens += budget
 	}
 
-	baseURL := defaultClaudeBaseURL
+	return reqBody
+}
+
+// endpoint returns the messages API URL.
+func (c *claudeProvider) endpoint() string {
 	if c.provider.BaseURL != "" {
-		baseURL = c.provider.BaseURL
+		return c.provider.BaseURL
+	}
+	return defaultClaudeBaseURL
+}
+
+// doClaude sends the request and returns the response if the status is OK.
+func (c *claudeProvider) doClaude(ctx context.Context, reqBody claudeRequest) (*http.Response, error) {
+	jsonBody, err := marshalRequest(c.provider, reqBody)
+	if err != nil {
+		return nil, err
 	}
 
-	req, err := http.NewRequest("POST", baseURL, bytes.NewBuffer(jsonBody))
+	req, err := http.NewRequestWithContext(ctx, "POST", c.endpoint(), bytes.NewBuffer(jsonBody))
 	if err != nil {
-		return "", types.Usage{}, fmt.Errorf("create request: %w", err)
+		return nil, fmt.Errorf("create request: %w", err)
 	}
 
 	req.Header.Set("x-api-key", c.provider.APIKey)
 	req.Header.Set("anthropic-version", "2023-06-01")
 	req.Header.Set("content-type", "application/json")
+	setHeaders(req, c.provider)
 
 	resp, err := c.http.Do(req)
 	if err != nil {
-		return "", types.Usage{}, fmt.Errorf("do request: %w", err)
+		return nil, requestError(ctx, err)
 	}
-	defer resp.Body.Close()
 
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return "", types.Usage{}, fmt.Errorf("read response: %w", err)
-	}
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
 
-	var claudeResp claudeResponse
-	if err := json.Unmarshal(body, &claudeResp); err != nil {
-		return "", types.Usage{}, fmt.Errorf("unmarshal response: %w", err)
-	}
-
-	if claudeResp.Error != nil {
-		return "", types.Usage{}, fmt.Errorf("api error: %s - %s", claudeResp.Error.Type, claudeResp.Error.Message)
-	}
-
-	if len(claudeResp.Content) == 0 {
-		return "", types.Usage{}, fmt.Errorf("no content returned")
-	}
-
-	var usage types.Usage
-	if claudeResp.Usage != nil {
-		usage = types.Usage{
-			PromptTokens:     claudeResp.Usage.InputTokens,
-			CompletionTokens: claudeResp.Usage.OutputTokens,
-			TotalTokens:      claudeResp.Usage.InputTokens + claudeResp.Usage.OutputTokens,
+		var claudeResp claudeResponse
+		var apiErr *Error
+		if json.Unmarshal(body, &claudeResp) == nil && claudeResp.Error != nil {
+			apiErr = claudeResp.Error.toError()
 		}
+		return nil, httpError(resp, body, apiErr)
 	}
 
-	return claudeResp.Content[0].Text, usage, nil
+	return resp, nil
 }
@@ -2,42 +2,200 @@
 package llm
 
 import (
+	"context"
+	"encoding/base64"
+	"fmt"
 	"net/http"
+	"net/url"
+	"time"
 
 	"go.aimuz.me/transy/internal/types"
 )
 
 // Message represents a chat message.
 type Message struct {
-	Role    string `json:"role"`
-	Content string `json:"content"`
+	Role    string  `json:"role"`
+	Content string  `json:"content"`
+	Images  []Image `json:"images,omitempty"` // sent alongside Content to vision-capable models
 }
 
-// Client is an HTTP client for LLM APIs.
+// Image is an image attached to a message.
+type Image struct {
+	MIMEType string // e.g. "image/png"
+	Data     []byte
+}
+
+// base64 returns the image data in standard base64 encoding.
+func (img Image) base64() string {
+	return base64.StdEncoding.EncodeToString(img.Data)
+}
+
+// dataURL returns the image as a data: URL.
+func (img Image) dataURL() string {
+	return "data:" + img.MIMEType + ";base64," + img.base64()
+}
+
+// Response is the result of a chat completion.
+type Response struct {
+	Text      string
+	Reasoning string // the model's reasoning, kept out of Text; empty if none
+	Usage     types.Usage
+}
+
+// Client is an HTTP client for LLM APIs. It is safe for concurrent use, and
+// reusing it lets requests share connections.
 type Client struct {
 	provider *types.Provider
+	backend  Provider
 	http     *http.Client
+	retry    RetryPolicy
+	timeout  time.Duration // overall limit per call including retries, 0 if none
 }
 
 // NewClient creates a new LLM client for the given provider.
-func NewClient(p *types.Provider) *Client {
+// It returns an error if the provider type is not registered or its
+// network settings cannot be applied.
+func NewClient(p *types.Provider) (*Client, error) {
+	r, ok := Lookup(p.Type)
+	if !ok {
+		return nil, fmt.Errorf("unknown provider type: %q", p.Type)
+	}
+
+	hc, err := newHTTPClient(p)
+	if err != nil {
+		return nil, fmt.Errorf("build http client: %w", err)
+	}
+
 	return &Client{
 		provider: p,
-		http:     &http.Client{},
+		backend:  r.New(p, hc),
+		http:     hc,
+		retry:    DefaultRetryPolicy,
+		timeout:  time.Duration(p.Timeout) * time.Second,
+	}, nil
+}
+
+// Complete sends a chat completion request and returns the response.
+// The request is aborted when ctx is cancelled. Rate-limited and transient
+// failures are retried; errors are reported as *Error where possible.
+// A leading <think> block in the text is moved to Response.Reasoning.
+func (c *Client) Complete(ctx context.Context, messages []Message) (resp Response, err error) {
+	ctx, span := c.startSpan(ctx, false)
+	defer func(ctx context.Context, start time.Time) {
+		c.endSpan(ctx, span, start, resp, err)
+	}(ctx, time.Now())
+
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+
+	err = c.retry.do(ctx, func() error {
+		var err error
+		resp, err = c.backend.Complete(ctx, messages)
+		return err
+	})
+	if err != nil {
+		return Response{}, err
 	}
+
+	var thought string
+	resp.Text, thought = splitThink(resp.Text)
+	resp.Reasoning = joinReasoning(resp.Reasoning, thought)
+	return resp, nil
+}
+
+// Stream sends a streaming chat completion request. fn is called with each
+// text delta as it arrives; the full response is returned once the stream
+// completes. A leading <think> block is diverted to Response.Reasoning
+// rather than passed to fn. Failures are only retried before the first
+// delta has been delivered.
+func (c *Client) Stream(ctx context.Context, messages []Message, fn StreamFunc) (resp Response, err error) {
+	ctx, span := c.startSpan(ctx, true)
+	defer func(ctx context.Context, start time.Time) {
+		c.endSpan(ctx, span, start, resp, err)
+	}(ctx, time.Now())
+
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+
+	started := false
+
+	err = c.retry.do(ctx, func() error {
+		split := newThinkSplitter(func(delta string) {
+			started = true
+			fn(delta)
+		})
+
+		var err error
+		resp, err = c.backend.Stream(ctx, messages, split.write)
+		if err != nil {
+			if started {
+				// Output already reached the caller; a retry would duplicate it.
+				return permanent(err)
+			}
+			return err
+		}
+
+		split.flush()
+		resp.Text = split.text.String()
+		resp.Reasoning = joinReasoning(resp.Reasoning, split.reasoning.String())
+		return nil
+	})
+	if err != nil {
+		return Response{}, err
+	}
+	return resp, nil
+}
+
+// ListModels returns the models offered by the provider, if the backend
+// supports listing them.
+func (c *Client) ListModels(ctx context.Context) ([]string, error) {
+	lister, ok := c.backend.(ModelLister)
+	if !ok {
+		return nil, fmt.Errorf("provider type %q does not support listing models", c.provider.Type)
+	}
+	return lister.ListModels(ctx)
+}
+
+// endpointer is implemented by backends to report the URL they send
+// requests to.
+type endpointer interface {
+	endpoint() string
+}
+
+// Warm opens a connection to the provider's host ahead of the first
+// request, so that the request doesn't wait for the TCP and TLS handshakes.
+// The host's root is requested with HEAD and whatever it answers is ignored.
+func (c *Client) Warm(ctx context.Context) error {
+	e, ok := c.backend.(endpointer)
+	if !ok {
+		return nil
+	}
+	u, err := url.Parse(e.endpoint())
+	if err != nil {
+		return fmt.Errorf("parse endpoint: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, u.Scheme+"://"+u.Host+"/", nil)
+	if err != nil {
+		return fmt.Errorf("create request: %w", err)
+	}
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return requestError(ctx, err)
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// CloseIdleConnections closes the client's idle connections.
+func (c *Client) CloseIdleConnections() {
+	c.http.CloseIdleConnections()
 }
 
-// Complete sends a chat completion request and returns the response text and usage.
-func (c *Client) Complete(messages []Message) (string, types.Usage, error) {
-	switch c.provider.Type {
-	case "gemini":
-		return c.completeGemini(messages)
-	case "claude":
-		return c.completeClaude(messages)
-	case "openai", "openai-compatible":
-		return c.completeOpenAI(messages)
-	default:
-		// Default to OpenAI format for compatibility
-		return c.completeOpenAI(messages)
+// withTimeout applies the provider's overall timeout to ctx, if any.
+func (c *Client) withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if c.timeout <= 0 {
+		return ctx, func() {}
 	}
+	return context.WithTimeout(ctx, c.timeout)
 }
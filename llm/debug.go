@@ -0,0 +1,223 @@
+package llm
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// maxLoggedBody bounds the bytes of each body written to the debug log;
+// requests carrying images can be several megabytes.
+const maxLoggedBody = 64 << 10
+
+// minSecretLen is the length below which a provider's API key is not
+// searched for in logged text, so that a short test key doesn't mangle it.
+// Credential headers and query parameters are always redacted.
+const minSecretLen = 8
+
+const redacted = "[REDACTED]"
+
+// debugLog is where exchanges are recorded, or nil when debug logging is off.
+var debugLog atomic.Pointer[io.Writer]
+
+// SetDebugLog makes every client record its HTTP requests and responses to
+// w, one JSON object per line. API keys and credential headers are redacted.
+// A nil w turns recording off. w must be safe for concurrent use.
+func SetDebugLog(w io.Writer) {
+	if w == nil {
+		debugLog.Store(nil)
+		return
+	}
+	debugLog.Store(&w)
+}
+
+// debugRecord is one request and its response as written to the debug log.
+type debugRecord struct {
+	Time            time.Time         `json:"time"`
+	Provider        string            `json:"provider"`
+	Method          string            `json:"method"`
+	URL             string            `json:"url"`
+	RequestHeaders  map[string]string `json:"request_headers,omitempty"`
+	RequestBody     string            `json:"request_body,omitempty"`
+	Status          int               `json:"status,omitempty"`
+	ResponseHeaders map[string]string `json:"response_headers,omitempty"`
+	ResponseBody    string            `json:"response_body,omitempty"`
+	DurationMs      int64             `json:"duration_ms"`
+	Error           string            `json:"error,omitempty"`
+}
+
+// debugTransport records exchanges to the debug log while it is set.
+// Streamed responses are recorded once their body is closed.
+type debugTransport struct {
+	next     http.RoundTripper
+	provider string
+	secret   string // the provider's API key, redacted wherever it appears
+}
+
+func (t *debugTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	w := debugLog.Load()
+	if w == nil {
+		return t.next.RoundTrip(req)
+	}
+
+	start := time.Now()
+	rec := &debugRecord{
+		Time:           start,
+		Provider:       t.provider,
+		Method:         req.Method,
+		URL:            t.redact(redactURL(req.URL)),
+		RequestHeaders: t.headers(req.Header),
+	}
+	if req.GetBody != nil {
+		if body, err := req.GetBody(); err == nil {
+			data, n, _ := readLimited(body)
+			body.Close()
+			rec.RequestBody = t.redact(truncated(data, n))
+		}
+	}
+
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		rec.Error = t.redact(err.Error())
+		rec.DurationMs = time.Since(start).Milliseconds()
+		writeRecord(*w, rec)
+		return nil, err
+	}
+
+	rec.Status = resp.StatusCode
+	rec.ResponseHeaders = t.headers(resp.Header)
+	resp.Body = &recordedBody{ReadCloser: resp.Body, t: t, w: *w, rec: rec, start: start}
+	return resp, nil
+}
+
+// CloseIdleConnections lets http.Client.CloseIdleConnections reach the
+// wrapped transport.
+func (t *debugTransport) CloseIdleConnections() {
+	if c, ok := t.next.(interface{ CloseIdleConnections() }); ok {
+		c.CloseIdleConnections()
+	}
+}
+
+// headers returns h with credentials redacted, one value per name.
+func (t *debugTransport) headers(h http.Header) map[string]string {
+	out := make(map[string]string, len(h))
+	for name, values := range h {
+		v := strings.Join(values, ", ")
+		if sensitiveName(name) {
+			v = redacted
+		}
+		out[name] = t.redact(v)
+	}
+	return out
+}
+
+// redact replaces the provider's API key in s.
+func (t *debugTransport) redact(s string) string {
+	if len(t.secret) < minSecretLen {
+		return s
+	}
+	return strings.ReplaceAll(s, t.secret, redacted)
+}
+
+// recordedBody captures the start of a response body as it is read and
+// writes the record when the body is closed.
+type recordedBody struct {
+	io.ReadCloser
+	t     *debugTransport
+	w     io.Writer
+	rec   *debugRecord
+	start time.Time
+
+	buf  bytes.Buffer
+	n    int // bytes read in total
+	once sync.Once
+}
+
+func (b *recordedBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	if room := maxLoggedBody - b.buf.Len(); room > 0 {
+		b.buf.Write(p[:min(n, room)])
+	}
+	b.n += n
+	return n, err
+}
+
+func (b *recordedBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.once.Do(func() {
+		b.rec.ResponseBody = b.t.redact(truncated(b.buf.Bytes(), b.n))
+		b.rec.DurationMs = time.Since(b.start).Milliseconds()
+		writeRecord(b.w, b.rec)
+	})
+	return err
+}
+
+// readLimited reads r to the end, keeping at most maxLoggedBody bytes, and
+// returns them with the total length.
+func readLimited(r io.Reader) ([]byte, int, error) {
+	var buf bytes.Buffer
+	n, err := io.Copy(&buf, io.LimitReader(r, maxLoggedBody))
+	if err != nil {
+		return buf.Bytes(), int(n), err
+	}
+	rest, err := io.Copy(io.Discard, r)
+	return buf.Bytes(), int(n + rest), err
+}
+
+// truncated returns data, noting how much of a body of n bytes was cut off.
+func truncated(data []byte, n int) string {
+	if n <= len(data) {
+		return string(data)
+	}
+	return fmt.Sprintf("%s…[%d bytes truncated]", data, n-len(data))
+}
+
+func writeRecord(w io.Writer, rec *debugRecord) {
+	line, err := json.Marshal(rec)
+	if err != nil {
+		return
+	}
+	_, _ = w.Write(append(line, '\n'))
+}
+
+// sensitiveName reports whether a header or query parameter carries
+// credentials.
+func sensitiveName(name string) bool {
+	name = strings.ToLower(name)
+	switch name {
+	case "authorization", "proxy-authorization", "cookie", "set-cookie":
+		return true
+	}
+	for _, s := range []string{"key", "token", "secret", "auth"} {
+		if strings.Contains(name, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// redactURL returns u with its password and credential query parameters
+// redacted.
+func redactURL(u *url.URL) string {
+	cp := *u
+	if _, ok := cp.User.Password(); ok {
+		cp.User = url.UserPassword(cp.User.Username(), redacted)
+	}
+	if cp.RawQuery != "" {
+		q := cp.Query()
+		for name := range q {
+			if sensitiveName(name) {
+				q.Set(name, redacted)
+			}
+		}
+		cp.RawQuery = q.Encode()
+	}
+	return cp.String()
+}
@@ -0,0 +1,188 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// ErrorCode classifies why an LLM API call failed.
+type ErrorCode string
+
+const (
+	CodeAuth           ErrorCode = "auth"            // invalid or missing credentials
+	CodeRateLimited    ErrorCode = "rate_limited"    // too many requests, retry later
+	CodeQuotaExhausted ErrorCode = "quota_exhausted" // billing quota or credits used up
+	CodeContentBlocked ErrorCode = "content_blocked" // refused by a safety filter
+	CodeTransient      ErrorCode = "transient"       // network failure or server-side error
+	CodeBadRequest     ErrorCode = "bad_request"     // malformed request or unknown model
+	CodeUnavailable    ErrorCode = "unavailable"     // server not reachable, e.g. local daemon not running
+	CodeCanceled       ErrorCode = "canceled"        // cancelled by the caller
+	CodeUnknown        ErrorCode = "unknown"
+)
+
+// Error is a classified LLM API failure.
+type Error struct {
+	Code       ErrorCode
+	StatusCode int           // HTTP status, 0 if the failure was not an HTTP error
+	Message    string        // provider-supplied message
+	RetryAfter time.Duration // server-requested delay before retrying, 0 if none
+	Err        error         // underlying error, if any
+}
+
+func (e *Error) Error() string {
+	if e.StatusCode != 0 {
+		return fmt.Sprintf("api error (%s): %d - %s", e.Code, e.StatusCode, e.Message)
+	}
+	return fmt.Sprintf("api error (%s): %s", e.Code, e.Message)
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Retryable reports whether the same request may succeed if sent again.
+func (e *Error) Retryable() bool {
+	return e.Code == CodeRateLimited || e.Code == CodeTransient
+}
+
+// Failover reports whether a request that failed with err should be sent to
+// another provider: the failure is specific to the provider that served it
+// (outage, rate limit or exhausted quota) rather than to the request itself.
+func Failover(err error) bool {
+	switch CodeOf(err) {
+	case CodeTransient, CodeRateLimited, CodeQuotaExhausted, CodeUnavailable:
+		return true
+	}
+	return false
+}
+
+// CodeOf returns the ErrorCode of err, or "" if err is nil.
+// Errors not produced by this package are reported as CodeUnknown.
+func CodeOf(err error) ErrorCode {
+	if err == nil {
+		return ""
+	}
+	if errors.Is(err, context.Canceled) {
+		return CodeCanceled
+	}
+	var e *Error
+	if errors.As(err, &e) {
+		return e.Code
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return CodeTransient
+	}
+	return CodeUnknown
+}
+
+// httpError classifies a non-2xx response by its status. apiErr is the
+// provider's error object parsed from body, or nil if there was none; its
+// code only refines the status, telling exhausted quotas from rate limits
+// and safety refusals from other bad requests. The free text of body is
+// never searched, as it may quote the request.
+func httpError(resp *http.Response, body []byte, apiErr *Error) *Error {
+	e := &Error{
+		Code:       CodeUnknown,
+		StatusCode: resp.StatusCode,
+		Message:    strings.TrimSpace(string(body)),
+		RetryAfter: parseRetryAfter(resp.Header),
+	}
+	var detail ErrorCode
+	if apiErr != nil {
+		detail = apiErr.Code
+		if apiErr.Message != "" {
+			e.Message = apiErr.Message
+		}
+	}
+
+	switch status := resp.StatusCode; {
+	case status == http.StatusUnauthorized || status == http.StatusForbidden:
+		e.Code = CodeAuth
+	case status == http.StatusPaymentRequired:
+		e.Code = CodeQuotaExhausted
+	case status == http.StatusTooManyRequests:
+		e.Code = CodeRateLimited
+		if detail == CodeQuotaExhausted {
+			e.Code = detail
+		}
+	case status == http.StatusRequestTimeout,
+		status == 529, // Anthropic: overloaded
+		status >= 500:
+		e.Code = CodeTransient
+	case status >= 400:
+		e.Code = CodeBadRequest
+		if detail == CodeQuotaExhausted || detail == CodeContentBlocked {
+			e.Code = detail
+		}
+	}
+	return e
+}
+
+// requestError wraps a transport-level failure from http.Client.Do.
+func requestError(ctx context.Context, err error) error {
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return fmt.Errorf("do request: %w", ctxErr)
+	}
+	if errors.Is(err, syscall.ECONNREFUSED) {
+		return &Error{Code: CodeUnavailable, Message: err.Error(), Err: err}
+	}
+	return &Error{Code: CodeTransient, Message: err.Error(), Err: err}
+}
+
+// blockedError reports a response that was refused by a safety filter.
+func blockedError(reason string) *Error {
+	return &Error{Code: CodeContentBlocked, Message: "response blocked: " + reason}
+}
+
+// truncatedError reports a stream that ended before the provider marked it
+// complete, e.g. because the connection dropped; the text so far is partial.
+func truncatedError() *Error {
+	return &Error{Code: CodeTransient, Message: "stream ended before the response was complete"}
+}
+
+// isQuotaMessage reports whether s, a provider's error code or a message
+// already known to be about limits, means the quota is used up.
+func isQuotaMessage(s string) bool {
+	return strings.Contains(s, "insufficient_quota") ||
+		strings.Contains(s, "exceeded your current quota") ||
+		strings.Contains(s, "credit balance") ||
+		strings.Contains(s, "billing")
+}
+
+// isBlockedMessage reports whether s, a provider's error code, means a
+// safety refusal.
+func isBlockedMessage(s string) bool {
+	return strings.Contains(s, "content_filter") ||
+		strings.Contains(s, "content_policy") ||
+		strings.Contains(s, "safety")
+}
+
+// parseRetryAfter reads the delay requested by the server, preferring the
+// millisecond-precision retry-after-ms header used by OpenAI and Azure.
+func parseRetryAfter(h http.Header) time.Duration {
+	if v := h.Get("retry-after-ms"); v != "" {
+		if ms, err := strconv.ParseFloat(v, 64); err == nil && ms > 0 {
+			return time.Duration(ms * float64(time.Millisecond))
+		}
+	}
+
+	v := h.Get("Retry-After")
+	if v == "" {
+		return 0
+	}
+	if secs, err := strconv.ParseFloat(v, 64); err == nil && secs > 0 {
+		return time.Duration(secs * float64(time.Second))
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		if d := time.Until(t); d > 0 {
+			return d
+		}
+	}
+	return 0
+}
@@ -0,0 +1,160 @@
+package llm
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go.aimuz.me/transy/internal/types"
+)
+
+func TestParseRetryAfter(t *testing.T) {
+	tests := []struct {
+		name   string
+		header http.Header
+		want   time.Duration
+	}{
+		{"none", http.Header{}, 0},
+		{"seconds", http.Header{"Retry-After": {"2"}}, 2 * time.Second},
+		{"milliseconds preferred", http.Header{"Retry-After": {"2"}, "Retry-After-Ms": {"150"}}, 150 * time.Millisecond},
+		{"invalid", http.Header{"Retry-After": {"soon"}}, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseRetryAfter(tt.header); got != tt.want {
+				t.Errorf("parseRetryAfter() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestErrorClassification(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		body   string
+		want   ErrorCode
+	}{
+		{"auth", http.StatusUnauthorized, `{"error":{"message":"bad key"}}`, CodeAuth},
+		{"rate limited", http.StatusTooManyRequests, `{"error":{"message":"slow down"}}`, CodeRateLimited},
+		{"quota", http.StatusTooManyRequests, `{"error":{"code":"insufficient_quota"}}`, CodeQuotaExhausted},
+		{"blocked", http.StatusBadRequest, `{"error":{"code":"content_filter"}}`, CodeContentBlocked},
+		{"bad request", http.StatusBadRequest, `{"error":{"message":"unknown model"}}`, CodeBadRequest},
+		{"server error", http.StatusBadGateway, `oops`, CodeTransient},
+		{"payment required", http.StatusPaymentRequired, `{"error":{"message":"add credits"}}`, CodeQuotaExhausted},
+		// Messages may quote the request; only the error code counts.
+		{"invalid safety settings", http.StatusBadRequest,
+			`{"error":{"type":"invalid_request_error","message":"Unknown field safetySettings"}}`, CodeBadRequest},
+		{"billing in message", http.StatusBadRequest, `{"error":{"message":"billing_address is not a valid parameter"}}`, CodeBadRequest},
+		{"server error mentioning billing", http.StatusInternalServerError, `{"error":{"message":"billing service unavailable"}}`, CodeTransient},
+		{"unparsed body", http.StatusBadRequest, `insufficient_quota`, CodeBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(tt.body))
+			}))
+			defer srv.Close()
+
+			client := newTestClient(t, srv.URL)
+			client.retry.MaxRetries = 0
+
+			_, err := client.Complete(context.Background(), []Message{{Role: "user", Content: "hi"}})
+			if got := CodeOf(err); got != tt.want {
+				t.Errorf("CodeOf(%v) = %q, want %q", err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFailover(t *testing.T) {
+	tests := []struct {
+		err  error
+		want bool
+	}{
+		{nil, false},
+		{&Error{Code: CodeTransient}, true},
+		{&Error{Code: CodeRateLimited}, true},
+		{&Error{Code: CodeQuotaExhausted}, true},
+		{&Error{Code: CodeUnavailable}, true},
+		{&Error{Code: CodeAuth}, false},
+		{&Error{Code: CodeBadRequest}, false},
+		{&Error{Code: CodeContentBlocked}, false},
+		{context.Canceled, false},
+	}
+
+	for _, tt := range tests {
+		if got := Failover(tt.err); got != tt.want {
+			t.Errorf("Failover(%v) = %v, want %v", tt.err, got, tt.want)
+		}
+	}
+}
+
+func TestRetry(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) < 3 {
+			w.Header().Set("retry-after-ms", "1")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		_, _ = w.Write([]byte(`{"choices":[{"message":{"content":"ok"}}]}`))
+	}))
+	defer srv.Close()
+
+	client := newTestClient(t, srv.URL)
+
+	resp, err := client.Complete(context.Background(), []Message{{Role: "user", Content: "hi"}})
+	if err != nil {
+		t.Fatalf("complete: %v", err)
+	}
+	if resp.Text != "ok" {
+		t.Errorf("text = %q, want %q", resp.Text, "ok")
+	}
+	if n := calls.Load(); n != 3 {
+		t.Errorf("calls = %d, want 3", n)
+	}
+}
+
+func TestNoRetryOnAuthError(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer srv.Close()
+
+	client := newTestClient(t, srv.URL)
+
+	_, err := client.Complete(context.Background(), []Message{{Role: "user", Content: "hi"}})
+	if CodeOf(err) != CodeAuth {
+		t.Errorf("code = %q, want %q", CodeOf(err), CodeAuth)
+	}
+	if n := calls.Load(); n != 1 {
+		t.Errorf("calls = %d, want 1", n)
+	}
+}
+
+// newTestClient returns an openai-compatible client pointed at url with
+// retry delays short enough for tests.
+func newTestClient(t *testing.T, url string) *Client {
+	t.Helper()
+
+	client, err := NewClient(&types.Provider{
+		Type:    "openai-compatible",
+		BaseURL: url,
+		APIKey:  "test",
+		Model:   "test-model",
+	})
+	if err != nil {
+		t.Fatalf("new client: %v", err)
+	}
+	client.retry = RetryPolicy{MaxRetries: 3, BaseDelay: time.Millisecond, MaxDelay: 10 * time.Millisecond}
+	return client
+}
@@ -0,0 +1,72 @@
+package llm
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"go.aimuz.me/transy/internal/types"
+)
+
+// marshalRequest encodes reqBody and merges the provider's extra body fields
+// into it. Nested objects are merged key by key, so extras can add e.g. topP
+// to Gemini's generationConfig without replacing the fields set by the
+// backend; any other value in ExtraBody overrides the generated one.
+func marshalRequest(p *types.Provider, reqBody any) ([]byte, error) {
+	jsonBody, err := json.Marshal(reqBody)
+	if err != nil {
+		return nil, fmt.Errorf("marshal request: %w", err)
+	}
+	if len(p.ExtraBody) == 0 {
+		return jsonBody, nil
+	}
+
+	var body map[string]any
+	dec := json.NewDecoder(bytes.NewReader(jsonBody))
+	dec.UseNumber() // keep integers such as max_tokens intact
+	if err := dec.Decode(&body); err != nil {
+		return nil, fmt.Errorf("decode request: %w", err)
+	}
+	mergeJSON(body, p.ExtraBody)
+
+	jsonBody, err = json.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("marshal request: %w", err)
+	}
+	return jsonBody, nil
+}
+
+// mergeJSON merges src into dst, recursing into objects present in both.
+func mergeJSON(dst, src map[string]any) {
+	for k, v := range src {
+		srcObj, ok := v.(map[string]any)
+		if dstObj, isObj := dst[k].(map[string]any); ok && isObj {
+			mergeJSON(dstObj, srcObj)
+			continue
+		}
+		dst[k] = v
+	}
+}
+
+// setHeaders applies the provider's custom headers. They are set after the
+// backend's own headers, so they may override them.
+func setHeaders(req *http.Request, p *types.Provider) {
+	for k, v := range p.Headers {
+		req.Header.Set(k, v)
+	}
+}
+
+// validateExtras checks the custom headers shared by all backends.
+func validateExtras(p *types.Provider) error {
+	for k, v := range p.Headers {
+		if k == "" || strings.ContainsAny(k, " \t\r\n:") {
+			return fmt.Errorf("invalid header name %q", k)
+		}
+		if strings.ContainsAny(v, "\r\n") {
+			return fmt.Errorf("invalid value for header %q", k)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,117 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go.aimuz.me/transy/internal/types"
+)
+
+func TestMarshalRequest(t *testing.T) {
+	p := &types.Provider{
+		ExtraBody: map[string]any{
+			"top_p":            0.9,
+			"seed":             42,
+			"generationConfig": map[string]any{"topK": 40},
+			"response_format":  map[string]any{"type": "json_object"},
+		},
+	}
+	reqBody := geminiRequest{GenerationConfig: geminiConfig{MaxOutputTokens: 1000}}
+
+	jsonBody, err := marshalRequest(p, reqBody)
+	if err != nil {
+		t.Fatalf("marshalRequest: %v", err)
+	}
+
+	var got struct {
+		TopP             float64 `json:"top_p"`
+		Seed             int     `json:"seed"`
+		GenerationConfig struct {
+			MaxOutputTokens int `json:"maxOutputTokens"`
+			TopK            int `json:"topK"`
+		} `json:"generationConfig"`
+		ResponseFormat struct {
+			Type string `json:"type"`
+		} `json:"response_format"`
+	}
+	if err := json.Unmarshal(jsonBody, &got); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+
+	if got.TopP != 0.9 || got.Seed != 42 {
+		t.Errorf("top-level extras not merged: %s", jsonBody)
+	}
+	if got.GenerationConfig.MaxOutputTokens != 1000 || got.GenerationConfig.TopK != 40 {
+		t.Errorf("nested object not merged key by key: %s", jsonBody)
+	}
+	if got.ResponseFormat.Type != "json_object" {
+		t.Errorf("response_format not added: %s", jsonBody)
+	}
+}
+
+func TestCustomHeadersAndBody(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("HTTP-Referer"); got != "https://example.com" {
+			t.Errorf("HTTP-Referer = %q", got)
+		}
+		if got := r.Header.Get("X-Tenant-ID"); got != "acme" {
+			t.Errorf("X-Tenant-ID = %q", got)
+		}
+
+		body, _ := io.ReadAll(r.Body)
+		var req map[string]any
+		_ = json.Unmarshal(body, &req)
+		if req["seed"] != float64(7) {
+			t.Errorf("seed = %v, body %s", req["seed"], body)
+		}
+		if req["model"] != "test-model" {
+			t.Errorf("model = %v, body %s", req["model"], body)
+		}
+
+		_, _ = w.Write([]byte(`{"choices":[{"message":{"content":"ok"}}]}`))
+	}))
+	defer srv.Close()
+
+	client, err := NewClient(&types.Provider{
+		Type:      "openai-compatible",
+		BaseURL:   srv.URL,
+		APIKey:    "k",
+		Model:     "test-model",
+		Headers:   map[string]string{"HTTP-Referer": "https://example.com", "X-Tenant-ID": "acme"},
+		ExtraBody: map[string]any{"seed": 7},
+	})
+	if err != nil {
+		t.Fatalf("new client: %v", err)
+	}
+
+	if _, err := client.Complete(context.Background(), []Message{{Role: "user", Content: "hi"}}); err != nil {
+		t.Fatalf("complete: %v", err)
+	}
+}
+
+func TestValidateExtras(t *testing.T) {
+	tests := []struct {
+		name    string
+		headers map[string]string
+		wantErr bool
+	}{
+		{"none", nil, false},
+		{"valid", map[string]string{"X-Tenant-ID": "acme"}, false},
+		{"empty name", map[string]string{"": "x"}, true},
+		{"colon in name", map[string]string{"X-A:": "x"}, true},
+		{"newline in value", map[string]string{"X-A": "a\r\nX-B: b"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateExtras(&types.Provider{Headers: tt.headers})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validateExtras() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
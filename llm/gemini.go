@@ -2,10 +2,13 @@ package llm
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
+	"strconv"
+	"strings"
 
 	"go.aimuz.me/transy/internal/types"
 )
@@ -13,8 +16,32 @@ import (
 // https://ai.google.dev/api/rest/v1beta/models/generateContent
 const defaultGeminiBaseURL = "https://generativelanguage.googleapis.com/v1beta/models"
 
+// geminiProvider implements Provider for the Gemini generateContent API.
+type geminiProvider struct {
+	provider *types.Provider
+	http     *http.Client
+}
+
+func init() {
+	Register(Registration{
+		Type:         "gemini",
+		Capabilities: Capabilities{Streaming: true},
+		Validate:     requireKeyAndModel,
+		New: func(p *types.Provider, hc *http.Client) Provider {
+			return &geminiProvider{provider: p, http: hc}
+		},
+	})
+}
+
 type geminiPart struct {
-	Text string `json:"text"`
+	Text       string      `json:"text,omitempty"`
+	InlineData *geminiBlob `json:"inlineData,omitempty"`
+	Thought    bool        `json:"thought,omitempty"` // set on thought summaries in responses
+}
+
+type geminiBlob struct {
+	MimeType string `json:"mimeType"`
+	Data     string `json:"data"` // base64
 }
 
 type geminiContent struct {
@@ -35,7 +62,8 @@ type geminiConfig struct {
 }
 
 type thinkingConfig struct {
-	ThinkingBudget int `json:"thinkingBudget"`
+	ThinkingBudget  int  `json:"thinkingBudget"`
+	IncludeThoughts bool `json:"includeThoughts,omitempty"`
 }
 
 type geminiSystemInst struct {
@@ -47,19 +75,165 @@ type geminiResponse struct {
 		Content struct {
 			Parts []geminiPart `json:"parts"`
 		} `json:"content"`
+		FinishReason string `json:"finishReason"`
 	} `json:"candidates"`
+	PromptFeedback *struct {
+		BlockReason string `json:"blockReason"`
+	} `json:"promptFeedback,omitempty"`
 	UsageMetadata *struct {
 		PromptTokenCount     int `json:"promptTokenCount"`
 		CandidatesTokenCount int `json:"candidatesTokenCount"`
 		TotalTokenCount      int `json:"totalTokenCount"`
 	} `json:"usageMetadata,omitempty"`
-	Error *struct {
-		Code    int    `json:"code"`
-		Message string `json:"message"`
-	} `json:"error,omitempty"`
+	Error *geminiError `json:"error,omitempty"`
+}
+
+type geminiError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+	Status  string `json:"status"`
+}
+
+// toError converts an in-band error object into an *Error.
+func (e *geminiError) toError() *Error {
+	code := CodeUnknown
+	switch e.Status {
+	case "UNAUTHENTICATED", "PERMISSION_DENIED":
+		code = CodeAuth
+	case "RESOURCE_EXHAUSTED":
+		code = CodeRateLimited
+		if isQuotaMessage(strings.ToLower(e.Message)) {
+			code = CodeQuotaExhausted
+		}
+	case "UNAVAILABLE", "INTERNAL", "DEADLINE_EXCEEDED":
+		code = CodeTransient
+	case "INVALID_ARGUMENT", "NOT_FOUND", "FAILED_PRECONDITION":
+		code = CodeBadRequest
+	}
+	return &Error{Code: code, Message: e.Message}
+}
+
+// blocked reports a safety block on the prompt or the first candidate.
+func (r *geminiResponse) blocked() error {
+	if r.PromptFeedback != nil && r.PromptFeedback.BlockReason != "" {
+		return blockedError(r.PromptFeedback.BlockReason)
+	}
+	for _, cand := range r.Candidates {
+		switch cand.FinishReason {
+		case "SAFETY", "PROHIBITED_CONTENT", "BLOCKLIST", "SPII", "RECITATION":
+			return blockedError(cand.FinishReason)
+		}
+	}
+	return nil
+}
+
+func (r *geminiResponse) usage() types.Usage {
+	if r.UsageMetadata == nil {
+		return types.Usage{}
+	}
+	return types.Usage{
+		PromptTokens:     r.UsageMetadata.PromptTokenCount,
+		CompletionTokens: r.UsageMetadata.CandidatesTokenCount,
+		TotalTokens:      r.UsageMetadata.TotalTokenCount,
+	}
+}
+
+func (g *geminiProvider) Complete(ctx context.Context, messages []Message) (Response, error) {
+	resp, err := g.doGemini(ctx, "generateContent", g.newGeminiRequest(messages))
+	if err != nil {
+		return Response{}, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return Response{}, fmt.Errorf("read response: %w", err)
+	}
+
+	var geminiResp geminiResponse
+	if err := json.Unmarshal(body, &geminiResp); err != nil {
+		return Response{}, fmt.Errorf("unmarshal response: %w", err)
+	}
+
+	if geminiResp.Error != nil {
+		return Response{}, geminiResp.Error.toError()
+	}
+
+	if err := geminiResp.blocked(); err != nil {
+		return Response{}, err
+	}
+
+	if len(geminiResp.Candidates) == 0 || len(geminiResp.Candidates[0].Content.Parts) == 0 {
+		return Response{}, fmt.Errorf("no candidates returned")
+	}
+
+	// Thought summaries, when requested, come as separate parts before the answer.
+	var text, reasoning strings.Builder
+	for _, part := range geminiResp.Candidates[0].Content.Parts {
+		if part.Thought {
+			reasoning.WriteString(part.Text)
+		} else {
+			text.WriteString(part.Text)
+		}
+	}
+
+	return Response{Text: text.String(), Reasoning: reasoning.String(), Usage: geminiResp.usage()}, nil
+}
+
+func (g *geminiProvider) Stream(ctx context.Context, messages []Message, fn StreamFunc) (Response, error) {
+	resp, err := g.doGemini(ctx, "streamGenerateContent?alt=sse", g.newGeminiRequest(messages))
+	if err != nil {
+		return Response{}, err
+	}
+	defer resp.Body.Close()
+
+	var text, reasoning strings.Builder
+	var usage types.Usage
+	done := false // whether a candidate's finish reason was seen
+
+	err = readSSE(resp.Body, func(ev sseEvent) error {
+		var chunk geminiResponse
+		if err := json.Unmarshal([]byte(ev.Data), &chunk); err != nil {
+			return fmt.Errorf("unmarshal chunk: %w", err)
+		}
+		if chunk.Error != nil {
+			return chunk.Error.toError()
+		}
+		if err := chunk.blocked(); err != nil {
+			return err
+		}
+		if chunk.UsageMetadata != nil {
+			usage = chunk.usage()
+		}
+		for _, cand := range chunk.Candidates {
+			if cand.FinishReason != "" {
+				done = true
+			}
+			for _, part := range cand.Content.Parts {
+				if part.Thought {
+					reasoning.WriteString(part.Text)
+					continue
+				}
+				if part.Text == "" {
+					continue
+				}
+				text.WriteString(part.Text)
+				fn(part.Text)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return Response{}, err
+	}
+	if !done {
+		return Response{}, truncatedError()
+	}
+
+	return Response{Text: text.String(), Reasoning: reasoning.String(), Usage: usage}, nil
 }
 
-func (c *Client) completeGemini(messages []Message) (string, types.Usage, error) {
+func (g *geminiProvider) newGeminiRequest(messages []Message) geminiRequest {
 	// Convert messages to Gemini format
 	var parts []geminiContent
 	var systemPrompt string
@@ -77,23 +251,41 @@ func (c *Client) completeGemini(messages []Message) (string, types.Usage, error)
 
 		parts = append(parts, geminiContent{
 			Role:  role,
-			Parts: []geminiPart{{Text: msg.Content}},
+			Parts: g.newGeminiParts(msg),
 		})
 	}
 
 	reqBody := geminiRequest{
 		Contents: parts,
 		GenerationConfig: geminiConfig{
-			MaxOutputTokens: c.provider.MaxTokens,
-			Temperature:     c.provider.Temperature,
+			MaxOutputTokens: g.provider.MaxTokens,
+			Temperature:     g.provider.Temperature,
 		},
 	}
 
-	// Disable thinking for Gemini 2.5 Flash models if requested
-	if c.provider.DisableThinking {
+	level := g.provider.Reasoning
+	if level == "" && g.provider.DisableThinking {
+		level = ReasoningOff
+	}
+	switch family := geminiThinkingFamily(g.provider.Model); {
+	case level == "", family == geminiNoThinking:
+		// Models before 2.5 reject thinkingConfig.
+	case level == ReasoningOff:
+		// Pro models can't turn thinking off; think as little as they can.
+		budget := 0
+		if family == geminiThinkingRequired {
+			budget = minGeminiProThinkingBudget
+		}
+		reqBody.GenerationConfig.ThinkingConfig = &thinkingConfig{ThinkingBudget: budget}
+		reqBody.GenerationConfig.MaxOutputTokens += budget
+	default:
+		// Thinking tokens count against maxOutputTokens.
+		budget := reasoningBudget(level)
 		reqBody.GenerationConfig.ThinkingConfig = &thinkingConfig{
-			ThinkingBudget: 0,
+			ThinkingBudget:  budget,
+			IncludeThoughts: true,
 		}
+		reqBody.GenerationConfig.MaxOutputTokens += budget
 	}
 
 	if systemPrompt != "" {
@@ -102,56 +294,100 @@ func (c *Client) completeGemini(messages []Message) (string, types.Usage, error)
 		}
 	}
 
-	jsonBody, err := json.Marshal(reqBody)
-	if err != nil {
-		return "", types.Usage{}, fmt.Errorf("marshal request: %w", err)
-	}
+	return reqBody
+}
 
-	baseURL := defaultGeminiBaseURL
-	if c.provider.BaseURL != "" {
-		baseURL = c.provider.BaseURL
-	}
+// geminiFamily groups Gemini models by the thinking they support.
+type geminiFamily int
 
-	url := fmt.Sprintf("%s/%s:generateContent?key=%s", baseURL, c.provider.Model, c.provider.APIKey)
+const (
+	geminiNoThinking       geminiFamily = iota // 1.x and 2.0: no thinkingConfig
+	geminiThinking                             // 2.5 and later Flash models: thinking can be turned off
+	geminiThinkingRequired                     // 2.5 and later Pro models: thinking can't be turned off
+)
 
-	req, err := http.NewRequest("POST", url, bytes.NewBuffer(jsonBody))
-	if err != nil {
-		return "", types.Usage{}, fmt.Errorf("create request: %w", err)
+// minGeminiProThinkingBudget is the smallest thinking budget Pro models
+// accept.
+const minGeminiProThinkingBudget = 128
+
+// geminiThinkingFamily returns the family of model, ignoring a "models/"
+// prefix. Versioned names before 2.5 and non-Gemini models such as Gemma
+// don't think; aliases without a version, such as gemini-flash-latest, are
+// taken to be current models.
+func geminiThinkingFamily(model string) geminiFamily {
+	m := strings.ToLower(model)
+	if i := strings.LastIndex(m, "/"); i >= 0 {
+		m = m[i+1:]
 	}
-	req.Header.Set("Content-Type", "application/json")
+	name, ok := strings.CutPrefix(m, "gemini-")
+	if !ok {
+		return geminiNoThinking
+	}
+	version, _, _ := strings.Cut(name, "-")
+	if v, err := strconv.ParseFloat(version, 64); err == nil && v < 2.5 {
+		return geminiNoThinking
+	}
+	if strings.Contains(name, "pro") {
+		return geminiThinkingRequired
+	}
+	return geminiThinking
+}
 
-	resp, err := c.http.Do(req)
-	if err != nil {
-		return "", types.Usage{}, fmt.Errorf("do request: %w", err)
+// newGeminiParts converts a message's text and images into content parts.
+func (g *geminiProvider) newGeminiParts(msg Message) []geminiPart {
+	parts := []geminiPart{{Text: msg.Content}}
+	for _, img := range msg.Images {
+		parts = append(parts, geminiPart{
+			InlineData: &geminiBlob{MimeType: img.MIMEType, Data: img.base64()},
+		})
 	}
-	defer resp.Body.Close()
+	return parts
+}
 
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return "", types.Usage{}, fmt.Errorf("read response: %w", err)
+// endpoint returns the URL of the models API.
+func (g *geminiProvider) endpoint() string {
+	if g.provider.BaseURL != "" {
+		return g.provider.BaseURL
 	}
+	return defaultGeminiBaseURL
+}
 
-	var geminiResp geminiResponse
-	if err := json.Unmarshal(body, &geminiResp); err != nil {
-		return "", types.Usage{}, fmt.Errorf("unmarshal response: %w", err)
+// doGemini sends the request to the given model method and returns the
+// response if the status is OK.
+func (g *geminiProvider) doGemini(ctx context.Context, method string, reqBody geminiRequest) (*http.Response, error) {
+	jsonBody, err := marshalRequest(g.provider, reqBody)
+	if err != nil {
+		return nil, err
 	}
 
-	if geminiResp.Error != nil {
-		return "", types.Usage{}, fmt.Errorf("api error: %d - %s", geminiResp.Error.Code, geminiResp.Error.Message)
+	url := fmt.Sprintf("%s/%s:%s", g.endpoint(), g.provider.Model, method)
+
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(jsonBody))
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
 	}
+	req.Header.Set("Content-Type", "application/json")
+	// Sent as a header rather than the key query parameter, which would
+	// show up in URLs quoted by transport errors.
+	req.Header.Set("x-goog-api-key", g.provider.APIKey)
+	setHeaders(req, g.provider)
 
-	if len(geminiResp.Candidates) == 0 || len(geminiResp.Candidates[0].Content.Parts) == 0 {
-		return "", types.Usage{}, fmt.Errorf("no candidates returned")
+	resp, err := g.http.Do(req)
+	if err != nil {
+		return nil, requestError(ctx, err)
 	}
 
-	var usage types.Usage
-	if geminiResp.UsageMetadata != nil {
-		usage = types.Usage{
-			PromptTokens:     geminiResp.UsageMetadata.PromptTokenCount,
-			CompletionTokens: geminiResp.UsageMetadata.CandidatesTokenCount,
-			TotalTokens:      geminiResp.UsageMetadata.TotalTokenCount,
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+
+		var geminiResp geminiResponse
+		var apiErr *Error
+		if json.Unmarshal(body, &geminiResp) == nil && geminiResp.Error != nil {
+			apiErr = geminiResp.Error.toError()
 		}
+		return nil, httpError(resp, body, apiErr)
 	}
 
-	return geminiResp.Candidates[0].Content.Parts[0].Text, usage, nil
+	return resp, nil
 }
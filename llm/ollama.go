@@ -0,0 +1,275 @@
+package llm
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"go.aimuz.me/transy/internal/types"
+)
+
+// Ollama serves its native API from the daemon root, so BaseURL is the
+// server address rather than a full endpoint URL.
+// https://github.com/ollama/ollama/blob/main/docs/api.md
+const defaultOllamaBaseURL = "http://localhost:11434"
+
+// ollamaProvider implements Provider for a local Ollama daemon.
+type ollamaProvider struct {
+	provider *types.Provider
+	http     *http.Client
+}
+
+func init() {
+	Register(Registration{
+		Type:         "ollama",
+		Capabilities: Capabilities{Streaming: true, ListModels: true},
+		Validate: func(p *types.Provider) error {
+			if p.Model == "" {
+				return fmt.Errorf("model required")
+			}
+			return nil
+		},
+		New: func(p *types.Provider, hc *http.Client) Provider {
+			return &ollamaProvider{provider: p, http: hc}
+		},
+	})
+}
+
+type ollamaMessage struct {
+	Role    string   `json:"role"`
+	Content string   `json:"content"`
+	Images  []string `json:"images,omitempty"` // base64, for vision models such as llava
+}
+
+type ollamaOptions struct {
+	NumCtx      int     `json:"num_ctx,omitempty"`
+	NumPredict  int     `json:"num_predict,omitempty"`
+	Temperature float64 `json:"temperature,omitempty"`
+}
+
+type ollamaRequest struct {
+	Model     string          `json:"model"`
+	Messages  []ollamaMessage `json:"messages"`
+	Stream    bool            `json:"stream"`
+	Options   *ollamaOptions  `json:"options,omitempty"`
+	KeepAlive string          `json:"keep_alive,omitempty"`
+	Think     *bool           `json:"think,omitempty"` // thinking models only; nil keeps the model default
+}
+
+// ollamaResponse is returned by /api/chat, once when not streaming or as
+// one JSON object per line when streaming.
+type ollamaResponse struct {
+	Message struct {
+		Content  string `json:"content"`
+		Thinking string `json:"thinking"` // set when think is enabled
+	} `json:"message"`
+	Done            bool   `json:"done"`
+	PromptEvalCount int    `json:"prompt_eval_count"`
+	EvalCount       int    `json:"eval_count"`
+	Error           string `json:"error,omitempty"`
+}
+
+type ollamaTagsResponse struct {
+	Models []struct {
+		Name string `json:"name"`
+	} `json:"models"`
+}
+
+func (r *ollamaResponse) usage() types.Usage {
+	return types.Usage{
+		PromptTokens:     r.PromptEvalCount,
+		CompletionTokens: r.EvalCount,
+		TotalTokens:      r.PromptEvalCount + r.EvalCount,
+	}
+}
+
+func (o *ollamaProvider) Complete(ctx context.Context, messages []Message) (Response, error) {
+	resp, err := o.do(ctx, "POST", "/api/chat", o.newOllamaRequest(messages, false))
+	if err != nil {
+		return Response{}, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return Response{}, fmt.Errorf("read response: %w", err)
+	}
+
+	var chatResp ollamaResponse
+	if err := json.Unmarshal(body, &chatResp); err != nil {
+		return Response{}, fmt.Errorf("unmarshal response: %w", err)
+	}
+	if chatResp.Error != "" {
+		return Response{}, &Error{Code: CodeUnknown, Message: chatResp.Error}
+	}
+
+	return Response{
+		Text:      chatResp.Message.Content,
+		Reasoning: chatResp.Message.Thinking,
+		Usage:     chatResp.usage(),
+	}, nil
+}
+
+func (o *ollamaProvider) Stream(ctx context.Context, messages []Message, fn StreamFunc) (Response, error) {
+	resp, err := o.do(ctx, "POST", "/api/chat", o.newOllamaRequest(messages, true))
+	if err != nil {
+		return Response{}, err
+	}
+	defer resp.Body.Close()
+
+	var text, reasoning strings.Builder
+	var usage types.Usage
+	done := false // whether the final chunk was seen
+
+	// Ollama streams newline-delimited JSON rather than SSE.
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxSSELineSize)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+
+		var chunk ollamaResponse
+		if err := json.Unmarshal(line, &chunk); err != nil {
+			return Response{}, fmt.Errorf("unmarshal chunk: %w", err)
+		}
+		if chunk.Error != "" {
+			return Response{}, &Error{Code: CodeUnknown, Message: chunk.Error}
+		}
+		reasoning.WriteString(chunk.Message.Thinking)
+		if chunk.Message.Content != "" {
+			text.WriteString(chunk.Message.Content)
+			fn(chunk.Message.Content)
+		}
+		if chunk.Done {
+			usage = chunk.usage()
+			done = true
+			break
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return Response{}, fmt.Errorf("read stream: %w", err)
+	}
+	if !done {
+		return Response{}, truncatedError()
+	}
+
+	return Response{Text: text.String(), Reasoning: reasoning.String(), Usage: usage}, nil
+}
+
+// ListModels returns the names of the models pulled into the daemon.
+func (o *ollamaProvider) ListModels(ctx context.Context) ([]string, error) {
+	resp, err := o.do(ctx, "GET", "/api/tags", nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var tags ollamaTagsResponse
+	if err := json.NewDecoder(resp.Body).Decode(&tags); err != nil {
+		return nil, fmt.Errorf("unmarshal response: %w", err)
+	}
+
+	names := make([]string, 0, len(tags.Models))
+	for _, m := range tags.Models {
+		names = append(names, m.Name)
+	}
+	return names, nil
+}
+
+func (o *ollamaProvider) newOllamaRequest(messages []Message, stream bool) ollamaRequest {
+	msgs := make([]ollamaMessage, 0, len(messages))
+	for _, msg := range messages {
+		m := ollamaMessage{Role: msg.Role, Content: msg.Content}
+		for _, img := range msg.Images {
+			m.Images = append(m.Images, img.base64())
+		}
+		msgs = append(msgs, m)
+	}
+
+	req := ollamaRequest{
+		Model:    o.provider.Model,
+		Messages: msgs,
+		Stream:   stream,
+		Options: &ollamaOptions{
+			NumCtx:      o.provider.NumCtx,
+			NumPredict:  o.provider.MaxTokens,
+			Temperature: o.provider.Temperature,
+		},
+		KeepAlive: o.provider.KeepAlive,
+	}
+
+	// Ollama only switches thinking on or off; num_predict covers both.
+	if level := o.provider.Reasoning; level != "" {
+		think := level != ReasoningOff
+		req.Think = &think
+		req.Options.NumPredict += reasoningBudget(level)
+	}
+	return req
+}
+
+// endpoint returns the URL of the daemon.
+func (o *ollamaProvider) endpoint() string {
+	if o.provider.BaseURL != "" {
+		return strings.TrimRight(o.provider.BaseURL, "/")
+	}
+	return defaultOllamaBaseURL
+}
+
+// do sends a request to the daemon and returns the response if the status is OK.
+// reqBody is JSON-encoded when non-nil.
+func (o *ollamaProvider) do(ctx context.Context, method, path string, reqBody any) (*http.Response, error) {
+	baseURL := o.endpoint()
+
+	var body io.Reader
+	if reqBody != nil {
+		jsonBody, err := marshalRequest(o.provider, reqBody)
+		if err != nil {
+			return nil, err
+		}
+		body = bytes.NewBuffer(jsonBody)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, baseURL+path, body)
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+	if reqBody != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if o.provider.APIKey != "" {
+		// Optional, for daemons behind an authenticating reverse proxy.
+		req.Header.Set("Authorization", "Bearer "+o.provider.APIKey)
+	}
+	setHeaders(req, o.provider)
+
+	resp, err := o.http.Do(req)
+	if err != nil {
+		err = requestError(ctx, err)
+		if CodeOf(err) == CodeUnavailable {
+			return nil, &Error{Code: CodeUnavailable, Message: "ollama is not running at " + baseURL, Err: err}
+		}
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		respBody, _ := io.ReadAll(resp.Body)
+
+		// Ollama's errors are free text, so only the status classifies them.
+		var errResp ollamaResponse
+		var apiErr *Error
+		if json.Unmarshal(respBody, &errResp) == nil && errResp.Error != "" {
+			apiErr = &Error{Code: CodeUnknown, Message: errResp.Error}
+		}
+		return nil, httpError(resp, respBody, apiErr)
+	}
+
+	return resp, nil
+}
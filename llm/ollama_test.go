@@ -0,0 +1,56 @@
+package llm
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"go.aimuz.me/transy/internal/types"
+)
+
+func TestOllamaListModels(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/tags" {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte(`{"models":[{"name":"qwen2.5:7b"},{"name":"llama3.2:latest"}]}`))
+	}))
+	defer srv.Close()
+
+	client, err := NewClient(&types.Provider{Type: "ollama", BaseURL: srv.URL, Model: "qwen2.5:7b"})
+	if err != nil {
+		t.Fatalf("new client: %v", err)
+	}
+
+	models, err := client.ListModels(context.Background())
+	if err != nil {
+		t.Fatalf("list models: %v", err)
+	}
+	if want := []string{"qwen2.5:7b", "llama3.2:latest"}; !slices.Equal(models, want) {
+		t.Errorf("models = %q, want %q", models, want)
+	}
+}
+
+func TestOllamaUnavailable(t *testing.T) {
+	// Reserve a port, then close it so nothing is listening.
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	addr := ln.Addr().String()
+	ln.Close()
+
+	client, err := NewClient(&types.Provider{Type: "ollama", BaseURL: "http://" + addr, Model: "m"})
+	if err != nil {
+		t.Fatalf("new client: %v", err)
+	}
+
+	_, err = client.ListModels(context.Background())
+	if CodeOf(err) != CodeUnavailable {
+		t.Errorf("code = %q (%v), want %q", CodeOf(err), err, CodeUnavailable)
+	}
+}
@@ -2,91 +2,361 @@ package llm
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
+	"strings"
 
 	"go.aimuz.me/transy/internal/types"
 )
 
 const defaultBaseURL = "https://api.openai.com/v1/chat/completions"
 
+// openaiProvider implements Provider for the OpenAI chat completions API.
+type openaiProvider struct {
+	provider *types.Provider
+	http     *http.Client
+}
+
+func init() {
+	newOpenAI := func(p *types.Provider, hc *http.Client) Provider {
+		return &openaiProvider{provider: p, http: hc}
+	}
+
+	Register(Registration{
+		Type:         "openai",
+		Capabilities: Capabilities{Streaming: true},
+		Validate:     requireKeyAndModel,
+		New:          newOpenAI,
+	})
+	Register(Registration{
+		Type:         "openai-compatible",
+		Capabilities: Capabilities{Streaming: true},
+		Validate: func(p *types.Provider) error {
+			if err := requireKeyAndModel(p); err != nil {
+				return err
+			}
+			if p.BaseURL == "" {
+				return fmt.Errorf("base url required for openai-compatible")
+			}
+			return nil
+		},
+		New: newOpenAI,
+	})
+}
+
 type openaiRequest struct {
-	Model       string    `json:"model"`
-	Messages    []Message `json:"messages"`
-	MaxTokens   int       `json:"max_tokens,omitempty"`
-	Temperature float64   `json:"temperature,omitempty"`
+	Model               string               `json:"model"`
+	Messages            []openaiMessage      `json:"messages"`
+	MaxTokens           int                  `json:"max_tokens,omitempty"`
+	MaxCompletionTokens int                  `json:"max_completion_tokens,omitempty"` // replaces max_tokens for reasoning models
+	ReasoningEffort     string               `json:"reasoning_effort,omitempty"`
+	Temperature         float64              `json:"temperature,omitempty"`
+	Stream              bool                 `json:"stream,omitempty"`
+	StreamOptions       *openaiStreamOptions `json:"stream_options,omitempty"`
+}
+
+// openaiMessage is a chat message whose content is a string, or an array
+// of content parts when images are attached.
+type openaiMessage struct {
+	Role    string `json:"role"`
+	Content any    `json:"content"`
+}
+
+type openaiContentPart struct {
+	Type     string          `json:"type"` // "text" or "image_url"
+	Text     string          `json:"text,omitempty"`
+	ImageURL *openaiImageURL `json:"image_url,omitempty"`
+}
+
+type openaiImageURL struct {
+	URL string `json:"url"` // data: URLs are accepted
+}
+
+type openaiStreamOptions struct {
+	IncludeUsage bool `json:"include_usage"`
+}
+
+type openaiUsage struct {
+	PromptTokens     int `json:"prompt_tokens"`
+	CompletionTokens int `json:"completion_tokens"`
+	TotalTokens      int `json:"total_tokens"`
+}
+
+type openaiError struct {
+	Message string `json:"message"`
+	Type    string `json:"type"`
+	Code    any    `json:"code"` // string or number depending on the server
 }
 
 type openaiResponse struct {
 	Choices []struct {
 		Message struct {
-			Content string `json:"content"`
+			Content          string `json:"content"`
+			ReasoningContent string `json:"reasoning_content"` // DeepSeek and compatible servers
 		} `json:"message"`
+		FinishReason string `json:"finish_reason"`
 	} `json:"choices"`
-	Usage struct {
-		PromptTokens     int `json:"prompt_tokens"`
-		CompletionTokens int `json:"completion_tokens"`
-		TotalTokens      int `json:"total_tokens"`
-	} `json:"usage"`
+	Usage openaiUsage  `json:"usage"`
+	Error *openaiError `json:"error,omitempty"`
 }
 
-func (c *Client) completeOpenAI(messages []Message) (string, types.Usage, error) {
-	url := defaultBaseURL
-	if c.provider.Type == "openai-compatible" && c.provider.BaseURL != "" {
-		url = c.provider.BaseURL
-	}
+type openaiStreamChunk struct {
+	Choices []struct {
+		Delta struct {
+			Content          string `json:"content"`
+			ReasoningContent string `json:"reasoning_content"`
+		} `json:"delta"`
+		FinishReason string `json:"finish_reason"`
+	} `json:"choices"`
+	Usage *openaiUsage `json:"usage,omitempty"`
+	Error *openaiError `json:"error,omitempty"`
+}
 
-	reqBody := openaiRequest{
-		Model:       c.provider.Model,
-		Messages:    messages,
-		MaxTokens:   c.provider.MaxTokens,
-		Temperature: c.provider.Temperature,
+func (u openaiUsage) toUsage() types.Usage {
+	return types.Usage{
+		PromptTokens:     u.PromptTokens,
+		CompletionTokens: u.CompletionTokens,
+		TotalTokens:      u.TotalTokens,
 	}
+}
 
-	jsonBody, err := json.Marshal(reqBody)
+func (o *openaiProvider) Complete(ctx context.Context, messages []Message) (Response, error) {
+	resp, err := o.doOpenAI(ctx, o.newOpenAIRequest(messages))
 	if err != nil {
-		return "", types.Usage{}, fmt.Errorf("marshal request: %w", err)
+		return Response{}, err
 	}
+	defer resp.Body.Close()
 
-	req, err := http.NewRequest("POST", url, bytes.NewBuffer(jsonBody))
+	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return "", types.Usage{}, fmt.Errorf("create request: %w", err)
+		return Response{}, fmt.Errorf("read response: %w", err)
 	}
 
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Authorization", "Bearer "+c.provider.APIKey)
+	var chatResp openaiResponse
+	if err := json.Unmarshal(body, &chatResp); err != nil {
+		return Response{}, fmt.Errorf("unmarshal response: %w", err)
+	}
+
+	if len(chatResp.Choices) == 0 {
+		return Response{}, fmt.Errorf("no choices")
+	}
+
+	if chatResp.Choices[0].FinishReason == "content_filter" {
+		return Response{}, blockedError("content_filter")
+	}
+
+	msg := chatResp.Choices[0].Message
+	return Response{
+		Text:      msg.Content,
+		Reasoning: msg.ReasoningContent,
+		Usage:     chatResp.Usage.toUsage(),
+	}, nil
+}
+
+func (o *openaiProvider) Stream(ctx context.Context, messages []Message, fn StreamFunc) (Response, error) {
+	reqBody := o.newOpenAIRequest(messages)
+	reqBody.Stream = true
+	reqBody.StreamOptions = &openaiStreamOptions{IncludeUsage: true}
 
-	resp, err := c.http.Do(req)
+	resp, err := o.doOpenAI(ctx, reqBody)
 	if err != nil {
-		return "", types.Usage{}, fmt.Errorf("do request: %w", err)
+		return Response{}, err
 	}
 	defer resp.Body.Close()
 
-	body, err := io.ReadAll(resp.Body)
+	var text, reasoning strings.Builder
+	var usage types.Usage
+	done := false // whether the end of the response was seen
+
+	err = readSSE(resp.Body, func(ev sseEvent) error {
+		if ev.Data == "[DONE]" {
+			done = true
+			return errStopStream
+		}
+
+		var chunk openaiStreamChunk
+		if err := json.Unmarshal([]byte(ev.Data), &chunk); err != nil {
+			return fmt.Errorf("unmarshal chunk: %w", err)
+		}
+		if chunk.Error != nil {
+			return chunk.Error.toError()
+		}
+		if chunk.Usage != nil {
+			usage = chunk.Usage.toUsage()
+		}
+		for _, choice := range chunk.Choices {
+			if choice.FinishReason == "content_filter" {
+				return blockedError("content_filter")
+			}
+			// Some compatible servers close the stream without [DONE].
+			if choice.FinishReason != "" {
+				done = true
+			}
+			reasoning.WriteString(choice.Delta.ReasoningContent)
+			if choice.Delta.Content == "" {
+				continue
+			}
+			text.WriteString(choice.Delta.Content)
+			fn(choice.Delta.Content)
+		}
+		return nil
+	})
 	if err != nil {
-		return "", types.Usage{}, fmt.Errorf("read response: %w", err)
+		return Response{}, err
+	}
+	if !done {
+		return Response{}, truncatedError()
 	}
 
-	if resp.StatusCode != http.StatusOK {
-		return "", types.Usage{}, fmt.Errorf("api error: %d - %s", resp.StatusCode, string(body))
+	return Response{Text: text.String(), Reasoning: reasoning.String(), Usage: usage}, nil
+}
+
+// toError converts an in-band stream error into an *Error.
+func (e *openaiError) toError() *Error {
+	detail := strings.ToLower(fmt.Sprint(e.Type, " ", e.Code))
+
+	code := CodeUnknown
+	switch {
+	case isQuotaMessage(detail):
+		code = CodeQuotaExhausted
+	case strings.Contains(detail, "rate_limit"):
+		code = CodeRateLimited
+	case isBlockedMessage(detail):
+		code = CodeContentBlocked
+	case strings.Contains(detail, "server_error"):
+		code = CodeTransient
 	}
+	return &Error{Code: code, Message: e.Message}
+}
 
-	var chatResp openaiResponse
-	if err := json.Unmarshal(body, &chatResp); err != nil {
-		return "", types.Usage{}, fmt.Errorf("unmarshal response: %w", err)
+func (o *openaiProvider) newOpenAIRequest(messages []Message) openaiRequest {
+	msgs := make([]openaiMessage, 0, len(messages))
+	for _, msg := range messages {
+		if len(msg.Images) == 0 {
+			msgs = append(msgs, openaiMessage{Role: msg.Role, Content: msg.Content})
+			continue
+		}
+
+		parts := []openaiContentPart{{Type: "text", Text: msg.Content}}
+		for _, img := range msg.Images {
+			parts = append(parts, openaiContentPart{
+				Type:     "image_url",
+				ImageURL: &openaiImageURL{URL: img.dataURL()},
+			})
+		}
+		msgs = append(msgs, openaiMessage{Role: msg.Role, Content: parts})
 	}
 
-	if len(chatResp.Choices) == 0 {
-		return "", types.Usage{}, fmt.Errorf("no choices")
+	req := openaiRequest{
+		Model:       o.provider.Model,
+		Messages:    msgs,
+		MaxTokens:   o.provider.MaxTokens,
+		Temperature: o.provider.Temperature,
+	}
+
+	family := openaiReasoningFamily(o.provider.Model)
+	if family == familyOther {
+		// Other models, and compatible servers, may reject or ignore the
+		// reasoning fields and would lose the limit and temperature; inline
+		// <think> output is split off by the client.
+		return req
+	}
+
+	// Reasoning models (o-series, GPT-5) reject max_tokens and any
+	// temperature other than the default, whatever the level.
+	level := o.provider.Reasoning
+	req.MaxTokens = 0
+	req.Temperature = 0
+	req.MaxCompletionTokens = o.provider.MaxTokens + reasoningBudget(level)
+	switch {
+	case level != ReasoningOff:
+		req.ReasoningEffort = level // empty leaves the model's default
+	case family == familyGPT5:
+		req.ReasoningEffort = "minimal" // o-series models can't reason less than their default
+	}
+	return req
+}
+
+// reasoningFamily groups OpenAI models by the reasoning parameters they
+// accept.
+type reasoningFamily int
+
+const (
+	familyOther reasoningFamily = iota // not known to reason, or not OpenAI's
+	familyO                            // o1, o3, o4-mini, …: low to high effort
+	familyGPT5                         // GPT-5: minimal to high effort
+)
+
+// openaiReasoningFamily returns the family of model, ignoring a provider
+// prefix such as "openai/" added by routers.
+func openaiReasoningFamily(model string) reasoningFamily {
+	m := strings.ToLower(model)
+	if i := strings.LastIndex(m, "/"); i >= 0 {
+		m = m[i+1:]
 	}
+	switch {
+	case strings.HasPrefix(m, "gpt-5-chat"): // the non-reasoning ChatGPT model
+		return familyOther
+	case strings.HasPrefix(m, "gpt-5"):
+		return familyGPT5
+	case len(m) >= 2 && m[0] == 'o' && m[1] >= '1' && m[1] <= '9':
+		return familyO
+	}
+	return familyOther
+}
+
+// endpoint returns the chat completions URL.
+func (o *openaiProvider) endpoint() string {
+	switch {
+	case o.provider.Type == "azure-openai":
+		return azureURL(o.provider)
+	case o.provider.Type == "openai-compatible" && o.provider.BaseURL != "":
+		return o.provider.BaseURL
+	}
+	return defaultBaseURL
+}
+
+// doOpenAI sends the request and returns the response if the status is OK.
+func (o *openaiProvider) doOpenAI(ctx context.Context, reqBody openaiRequest) (*http.Response, error) {
+	url := o.endpoint()
+
+	jsonBody, err := marshalRequest(o.provider, reqBody)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(jsonBody))
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	if o.provider.Type == "azure-openai" {
+		req.Header.Set("api-key", o.provider.APIKey)
+	} else {
+		req.Header.Set("Authorization", "Bearer "+o.provider.APIKey)
+	}
+	setHeaders(req, o.provider)
+
+	resp, err := o.http.Do(req)
+	if err != nil {
+		return nil, requestError(ctx, err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
 
-	usage := types.Usage{
-		PromptTokens:     chatResp.Usage.PromptTokens,
-		CompletionTokens: chatResp.Usage.CompletionTokens,
-		TotalTokens:      chatResp.Usage.TotalTokens,
+		var errResp openaiResponse
+		var apiErr *Error
+		if json.Unmarshal(body, &errResp) == nil && errResp.Error != nil {
+			apiErr = errResp.Error.toError()
+		}
+		return nil, httpError(resp, body, apiErr)
 	}
 
-	return chatResp.Choices[0].Message.Content, usage, nil
+	return resp, nil
 }
@@ -0,0 +1,99 @@
+package llm
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"sync"
+
+	"go.aimuz.me/transy/internal/types"
+)
+
+// Pool caches a Client per provider, so that requests reuse its connections
+// and TLS sessions instead of building a new HTTP client each time. A cached
+// client is rebuilt when its provider's configuration changes.
+type Pool struct {
+	mu      sync.Mutex
+	clients map[string]pooledClient // by provider name
+}
+
+type pooledClient struct {
+	fingerprint string
+	client      *Client
+}
+
+// NewPool returns an empty client pool.
+func NewPool() *Pool {
+	return &Pool{clients: make(map[string]pooledClient)}
+}
+
+// Get returns the cached client for p, creating one if there is none or if
+// p's configuration differs from the one the cached client was built with.
+func (pl *Pool) Get(p *types.Provider) (*Client, error) {
+	fp, err := fingerprint(p)
+	if err != nil {
+		return nil, err
+	}
+
+	pl.mu.Lock()
+	defer pl.mu.Unlock()
+
+	old, ok := pl.clients[p.Name]
+	if ok && old.fingerprint == fp {
+		return old.client, nil
+	}
+
+	// The client keeps the provider it was built with, so give it a copy
+	// the caller can't change under it, maps included. Values nested in
+	// ExtraBody are still shared; nothing edits them in place.
+	cp := *p
+	cp.Headers = maps.Clone(p.Headers)
+	cp.ExtraBody = maps.Clone(p.ExtraBody)
+	client, err := NewClient(&cp)
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		old.client.CloseIdleConnections()
+	}
+	pl.clients[p.Name] = pooledClient{fingerprint: fp, client: client}
+	return client, nil
+}
+
+// Invalidate drops the cached client for the named provider and closes its
+// idle connections. The next Get builds a new one.
+func (pl *Pool) Invalidate(name string) {
+	pl.mu.Lock()
+	defer pl.mu.Unlock()
+
+	if c, ok := pl.clients[name]; ok {
+		c.client.CloseIdleConnections()
+		delete(pl.clients, name)
+	}
+}
+
+// Close drops all cached clients and closes their idle connections.
+func (pl *Pool) Close() {
+	pl.mu.Lock()
+	defer pl.mu.Unlock()
+
+	for name, c := range pl.clients {
+		c.client.CloseIdleConnections()
+		delete(pl.clients, name)
+	}
+}
+
+// fingerprint identifies the parts of a provider's configuration a client
+// depends on. Whether the provider is active doesn't matter.
+func fingerprint(p *types.Provider) (string, error) {
+	cp := *p
+	cp.Active = false
+	data, err := json.Marshal(cp)
+	if err != nil {
+		return "", fmt.Errorf("marshal provider: %w", err)
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
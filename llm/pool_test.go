@@ -0,0 +1,95 @@
+package llm
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"go.aimuz.me/transy/internal/types"
+)
+
+func TestPoolReuse(t *testing.T) {
+	pool := NewPool()
+	p := &types.Provider{
+		Name:      "p",
+		Type:      "openai",
+		APIKey:    "k",
+		Model:     "m",
+		Headers:   map[string]string{"X-Title": "transy"},
+		ExtraBody: map[string]any{"top_p": 0.9},
+	}
+
+	c1, err := pool.Get(p)
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if c2, _ := pool.Get(p); c2 != c1 {
+		t.Error("same configuration built a new client")
+	}
+
+	p.Active = true
+	if c2, _ := pool.Get(p); c2 != c1 {
+		t.Error("activating the provider built a new client")
+	}
+
+	p.Model = "other"
+	c2, err := pool.Get(p)
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if c2 == c1 {
+		t.Error("changed configuration reused the old client")
+	}
+	if c2.provider.Model != "other" {
+		t.Errorf("model = %q, want %q", c2.provider.Model, "other")
+	}
+
+	// The pooled client keeps its own copy of the provider.
+	p.Model = "changed"
+	if c2.provider.Model != "other" {
+		t.Error("pooled client shares the caller's provider")
+	}
+	p.Headers["X-Title"] = "changed"
+	p.ExtraBody["top_p"] = 0.5
+	if c2.provider.Headers["X-Title"] != "transy" || c2.provider.ExtraBody["top_p"] != 0.9 {
+		t.Error("pooled client shares the caller's headers or extra body")
+	}
+
+	pool.Invalidate("p")
+	p.Model = "other"
+	if c3, _ := pool.Get(p); c3 == c2 {
+		t.Error("invalidated client was reused")
+	}
+}
+
+func TestWarmReusesConnection(t *testing.T) {
+	var conns atomic.Int32
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodHead {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(`{"choices":[{"message":{"content":"ok"}}]}`))
+	}))
+	srv.Config.ConnState = func(_ net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			conns.Add(1)
+		}
+	}
+	srv.Start()
+	defer srv.Close()
+
+	client := newTestClient(t, srv.URL+"/v1/chat/completions")
+	if err := client.Warm(context.Background()); err != nil {
+		t.Fatalf("warm: %v", err)
+	}
+	if _, err := client.Complete(context.Background(), []Message{{Role: "user", Content: "hi"}}); err != nil {
+		t.Fatalf("complete: %v", err)
+	}
+	if n := conns.Load(); n != 1 {
+		t.Errorf("opened %d connections, want 1", n)
+	}
+}
@@ -0,0 +1,129 @@
+package llm
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+
+	"go.aimuz.me/transy/internal/types"
+)
+
+// Provider is implemented by each LLM backend.
+type Provider interface {
+	// Complete sends a chat completion request and returns the response.
+	Complete(ctx context.Context, messages []Message) (Response, error)
+	// Stream is like Complete but calls fn with each text delta as it
+	// arrives. Reasoning the backend reports separately is not streamed.
+	Stream(ctx context.Context, messages []Message, fn StreamFunc) (Response, error)
+}
+
+// ModelLister is implemented by backends that can enumerate available models.
+type ModelLister interface {
+	ListModels(ctx context.Context) ([]string, error)
+}
+
+// Capabilities describes the optional features a backend supports.
+type Capabilities struct {
+	Streaming  bool `json:"streaming"`
+	ListModels bool `json:"listModels"` // backend implements ModelLister
+}
+
+// Registration describes a backend registered under a provider type name.
+type Registration struct {
+	// Type is the value of types.Provider.Type that selects this backend.
+	Type         string
+	Capabilities Capabilities
+	// Validate checks the backend-specific fields of a provider configuration.
+	Validate func(p *types.Provider) error
+	// New creates a backend for the given provider configuration.
+	New func(p *types.Provider, hc *http.Client) Provider
+}
+
+// TypeInfo is the public description of a registered provider type.
+type TypeInfo struct {
+	Type         string       `json:"type"`
+	Capabilities Capabilities `json:"capabilities"`
+}
+
+var (
+	registryMu sync.RWMutex
+	registry   = make(map[string]Registration)
+)
+
+// Register makes a backend available under r.Type.
+// It panics if r.Type is empty, r.New is nil, or the type is already registered.
+func Register(r Registration) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	if r.Type == "" || r.New == nil {
+		panic("llm: invalid registration")
+	}
+	if _, dup := registry[r.Type]; dup {
+		panic("llm: Register called twice for provider type " + r.Type)
+	}
+	registry[r.Type] = r
+}
+
+// Lookup returns the registration for the given provider type.
+func Lookup(typ string) (Registration, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	r, ok := registry[typ]
+	return r, ok
+}
+
+// Types returns all registered provider types sorted by name.
+func Types() []TypeInfo {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	infos := make([]TypeInfo, 0, len(registry))
+	for _, r := range registry {
+		infos = append(infos, TypeInfo{Type: r.Type, Capabilities: r.Capabilities})
+	}
+	slices.SortFunc(infos, func(a, b TypeInfo) int {
+		return strings.Compare(a.Type, b.Type)
+	})
+	return infos
+}
+
+// Validate checks that the provider type is registered and that the
+// configuration satisfies the network checks and the backend's validator.
+func Validate(p *types.Provider) error {
+	if p.Type == "" {
+		return fmt.Errorf("provider type required")
+	}
+	r, ok := Lookup(p.Type)
+	if !ok {
+		return fmt.Errorf("unknown provider type: %q", p.Type)
+	}
+	if err := validateNetwork(p); err != nil {
+		return err
+	}
+	if err := validateExtras(p); err != nil {
+		return err
+	}
+	if err := validateReasoning(p); err != nil {
+		return err
+	}
+	if r.Validate != nil {
+		return r.Validate(p)
+	}
+	return nil
+}
+
+// requireKeyAndModel is the validator shared by hosted backends.
+func requireKeyAndModel(p *types.Provider) error {
+	if p.APIKey == "" {
+		return fmt.Errorf("api key required")
+	}
+	if p.Model == "" {
+		return fmt.Errorf("model required")
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+package llm
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go.aimuz.me/transy/internal/types"
+)
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		p       types.Provider
+		wantErr bool
+	}{
+		{"openai", types.Provider{Type: "openai", APIKey: "k", Model: "m"}, false},
+		{"missing key", types.Provider{Type: "claude", Model: "m"}, true},
+		{"missing model", types.Provider{Type: "gemini", APIKey: "k"}, true},
+		{"compatible without base url", types.Provider{Type: "openai-compatible", APIKey: "k", Model: "m"}, true},
+		{"compatible with base url", types.Provider{Type: "openai-compatible", APIKey: "k", Model: "m", BaseURL: "http://x"}, false},
+		{"azure", types.Provider{Type: "azure-openai", APIKey: "k", Endpoint: "https://r.openai.azure.com", Deployment: "gpt-4o"}, false},
+		{"azure without deployment", types.Provider{Type: "azure-openai", APIKey: "k", Endpoint: "https://r.openai.azure.com"}, true},
+		{"azure bad endpoint", types.Provider{Type: "azure-openai", APIKey: "k", Endpoint: "r.openai.azure.com", Deployment: "d"}, true},
+		{"invalid reasoning", types.Provider{Type: "openai", APIKey: "k", Model: "m", Reasoning: "max"}, true},
+		{"empty type", types.Provider{APIKey: "k", Model: "m"}, true},
+		{"unknown type", types.Provider{Type: "foo", APIKey: "k", Model: "m"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Validate(&tt.p)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestNewClientUnknownType(t *testing.T) {
+	if _, err := NewClient(&types.Provider{Type: "foo"}); err == nil {
+		t.Error("expected error for unknown provider type")
+	}
+}
+
+func TestAzureRequest(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/openai/deployments/my-gpt/chat/completions" {
+			t.Errorf("path = %q", r.URL.Path)
+		}
+		if got := r.URL.Query().Get("api-version"); got != defaultAzureAPIVersion {
+			t.Errorf("api-version = %q, want %q", got, defaultAzureAPIVersion)
+		}
+		if got := r.Header.Get("api-key"); got != "k" {
+			t.Errorf("api-key header = %q", got)
+		}
+		if got := r.Header.Get("Authorization"); got != "" {
+			t.Errorf("unexpected Authorization header %q", got)
+		}
+		_, _ = w.Write([]byte(`{"choices":[{"message":{"content":"ok"}}]}`))
+	}))
+	defer srv.Close()
+
+	client, err := NewClient(&types.Provider{
+		Type:       "azure-openai",
+		APIKey:     "k",
+		Endpoint:   srv.URL + "/",
+		Deployment: "my-gpt",
+	})
+	if err != nil {
+		t.Fatalf("new client: %v", err)
+	}
+
+	resp, err := client.Complete(context.Background(), []Message{{Role: "user", Content: "hi"}})
+	if err != nil {
+		t.Fatalf("complete: %v", err)
+	}
+	if resp.Text != "ok" {
+		t.Errorf("text = %q, want %q", resp.Text, "ok")
+	}
+}
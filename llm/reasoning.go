@@ -0,0 +1,159 @@
+package llm
+
+import (
+	"fmt"
+	"strings"
+
+	"go.aimuz.me/transy/internal/types"
+)
+
+// Reasoning levels for types.Provider.Reasoning. An empty level leaves the
+// model's default behaviour untouched and sends no reasoning parameters.
+const (
+	ReasoningOff    = "off"
+	ReasoningLow    = "low"
+	ReasoningMedium = "medium"
+	ReasoningHigh   = "high"
+)
+
+// reasoningBudget returns the thinking token budget for a level, or 0 if
+// reasoning is off or unset. Backends that bound output by tokens add it
+// to MaxTokens so the reasoning does not eat into the translation.
+func reasoningBudget(level string) int {
+	switch level {
+	case ReasoningLow:
+		return 1024 // the minimum Claude accepts
+	case ReasoningMedium:
+		return 4096
+	case ReasoningHigh:
+		return 16384
+	}
+	return 0
+}
+
+// validateReasoning checks the reasoning level shared by all backends.
+func validateReasoning(p *types.Provider) error {
+	switch p.Reasoning {
+	case "", ReasoningOff, ReasoningLow, ReasoningMedium, ReasoningHigh:
+		return nil
+	}
+	return fmt.Errorf("invalid reasoning level %q: want off, low, medium or high", p.Reasoning)
+}
+
+const (
+	thinkOpen  = "<think>"
+	thinkClose = "</think>"
+)
+
+// splitThink separates a leading <think>…</think> block, as emitted inline by
+// DeepSeek-R1 style models, from the answer that follows it.
+func splitThink(s string) (text, reasoning string) {
+	t := strings.TrimLeft(s, " \t\r\n")
+	if !strings.HasPrefix(t, thinkOpen) {
+		return s, ""
+	}
+	t = t[len(thinkOpen):]
+
+	end := strings.Index(t, thinkClose)
+	if end < 0 {
+		return "", strings.TrimSpace(t) // unterminated: the model never answered
+	}
+	return strings.TrimLeft(t[end+len(thinkClose):], " \t\r\n"), strings.TrimSpace(t[:end])
+}
+
+// joinReasoning concatenates reasoning reported by different means.
+func joinReasoning(parts ...string) string {
+	var nonEmpty []string
+	for _, p := range parts {
+		if p = strings.TrimSpace(p); p != "" {
+			nonEmpty = append(nonEmpty, p)
+		}
+	}
+	return strings.Join(nonEmpty, "\n\n")
+}
+
+// thinkSplitter is the streaming counterpart of splitThink. It forwards text
+// deltas to fn and diverts a leading <think> block into reasoning, holding
+// back just enough input to recognise tags split across deltas.
+type thinkSplitter struct {
+	fn        StreamFunc
+	state     int
+	pending   string
+	text      strings.Builder
+	reasoning strings.Builder
+}
+
+const (
+	thinkDetect = iota // waiting to see whether the output opens with <think>
+	thinkInside        // inside the think block
+	thinkAfter         // just after </think>, dropping the whitespace that follows
+	thinkText          // plain text, forwarded as is
+)
+
+func newThinkSplitter(fn StreamFunc) *thinkSplitter {
+	return &thinkSplitter{fn: fn}
+}
+
+func (s *thinkSplitter) write(delta string) {
+	switch s.state {
+	case thinkDetect:
+		s.pending += delta
+		t := strings.TrimLeft(s.pending, " \t\r\n")
+		switch {
+		case t == "" || strings.HasPrefix(thinkOpen, t):
+			return // not enough input to decide yet
+		case strings.HasPrefix(t, thinkOpen):
+			s.pending = ""
+			s.state = thinkInside
+			s.write(t[len(thinkOpen):])
+		default:
+			t, s.pending = s.pending, ""
+			s.state = thinkText
+			s.emit(t)
+		}
+
+	case thinkInside:
+		s.pending += delta
+		if i := strings.Index(s.pending, thinkClose); i >= 0 {
+			s.reasoning.WriteString(s.pending[:i])
+			rest := s.pending[i+len(thinkClose):]
+			s.pending = ""
+			s.state = thinkAfter
+			s.write(rest)
+			return
+		}
+		// Keep a possible partial closing tag for the next delta.
+		if safe := len(s.pending) - (len(thinkClose) - 1); safe > 0 {
+			s.reasoning.WriteString(s.pending[:safe])
+			s.pending = s.pending[safe:]
+		}
+
+	case thinkAfter:
+		if t := strings.TrimLeft(delta, " \t\r\n"); t != "" {
+			s.state = thinkText
+			s.emit(t)
+		}
+
+	case thinkText:
+		s.emit(delta)
+	}
+}
+
+// flush releases any input held back once the stream has ended.
+func (s *thinkSplitter) flush() {
+	switch s.state {
+	case thinkDetect:
+		s.emit(s.pending)
+	case thinkInside:
+		s.reasoning.WriteString(s.pending)
+	}
+	s.pending = ""
+}
+
+func (s *thinkSplitter) emit(text string) {
+	if text == "" {
+		return
+	}
+	s.text.WriteString(text)
+	s.fn(text)
+}
@@ -0,0 +1,260 @@
+package llm
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go.aimuz.me/transy/internal/types"
+)
+
+func TestSplitThink(t *testing.T) {
+	tests := []struct {
+		name          string
+		in            string
+		wantText      string
+		wantReasoning string
+	}{
+		{"no think", "hello", "hello", ""},
+		{"think block", "<think>\nlet me see\n</think>\n\nhello", "hello", "let me see"},
+		{"leading whitespace", "\n<think>x</think>hello", "hello", "x"},
+		{"unterminated", "<think>still thinking", "", "still thinking"},
+		{"think later in text", "hello <think>x</think>", "hello <think>x</think>", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			text, reasoning := splitThink(tt.in)
+			if text != tt.wantText || reasoning != tt.wantReasoning {
+				t.Errorf("splitThink(%q) = %q, %q; want %q, %q", tt.in, text, reasoning, tt.wantText, tt.wantReasoning)
+			}
+		})
+	}
+}
+
+func TestThinkSplitter(t *testing.T) {
+	tests := []struct {
+		name          string
+		in            string
+		wantText      string
+		wantReasoning string
+	}{
+		{"no think", "hello world", "hello world", ""},
+		{"think block", "<think>\nlet me see\n</think>\n\nhello", "hello", "\nlet me see\n"},
+		{"short text", "<th", "<th", ""},
+		{"angle bracket", "<b>bold</b>", "<b>bold</b>", ""},
+	}
+
+	for _, tt := range tests {
+		// Feed the input whole, in pairs of bytes and byte by byte, so that
+		// tags are split across deltas at every position.
+		for _, size := range []int{len(tt.in), 2, 1} {
+			t.Run(tt.name, func(t *testing.T) {
+				var deltas strings.Builder
+				s := newThinkSplitter(func(delta string) { deltas.WriteString(delta) })
+				for i := 0; i < len(tt.in); i += size {
+					s.write(tt.in[i:min(i+size, len(tt.in))])
+				}
+				s.flush()
+
+				if got := s.text.String(); got != tt.wantText {
+					t.Errorf("chunk %d: text = %q, want %q", size, got, tt.wantText)
+				}
+				if got := deltas.String(); got != tt.wantText {
+					t.Errorf("chunk %d: streamed = %q, want %q", size, got, tt.wantText)
+				}
+				if got := s.reasoning.String(); got != tt.wantReasoning {
+					t.Errorf("chunk %d: reasoning = %q, want %q", size, got, tt.wantReasoning)
+				}
+			})
+		}
+	}
+}
+
+func TestStreamReasoning(t *testing.T) {
+	tests := []struct {
+		name string
+		typ  string
+		body string
+	}{
+		{
+			name: "openai reasoning_content",
+			typ:  "openai-compatible",
+			body: `data: {"choices":[{"delta":{"reasoning_content":"thinking"}}]}` + "\n\n" +
+				`data: {"choices":[{"delta":{"content":"你好"}}]}` + "\n\n" +
+				"data: [DONE]\n\n",
+		},
+		{
+			name: "inline think tags",
+			typ:  "openai-compatible",
+			body: `data: {"choices":[{"delta":{"content":"<thi"}}]}` + "\n\n" +
+				`data: {"choices":[{"delta":{"content":"nk>thinking</th"}}]}` + "\n\n" +
+				`data: {"choices":[{"delta":{"content":"ink>\n\n你好"}}]}` + "\n\n" +
+				"data: [DONE]\n\n",
+		},
+		{
+			name: "claude thinking",
+			typ:  "claude",
+			body: `data: {"type":"content_block_delta","delta":{"type":"thinking_delta","thinking":"thinking"}}` + "\n\n" +
+				`data: {"type":"content_block_delta","delta":{"type":"signature_delta"}}` + "\n\n" +
+				`data: {"type":"content_block_delta","delta":{"type":"text_delta","text":"你好"}}` + "\n\n" +
+				`data: {"type":"message_stop"}` + "\n\n",
+		},
+		{
+			name: "gemini thoughts",
+			typ:  "gemini",
+			body: `data: {"candidates":[{"content":{"parts":[{"text":"thinking","thought":true}]}}]}` + "\n\n" +
+				`data: {"candidates":[{"content":{"parts":[{"text":"你好"}]},"finishReason":"STOP"}]}` + "\n\n",
+		},
+		{
+			name: "ollama thinking",
+			typ:  "ollama",
+			body: `{"message":{"content":"","thinking":"thinking"},"done":false}` + "\n" +
+				`{"message":{"content":"你好"},"done":true}` + "\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				_, _ = w.Write([]byte(tt.body))
+			}))
+			defer srv.Close()
+
+			client, err := NewClient(&types.Provider{
+				Type:      tt.typ,
+				BaseURL:   srv.URL,
+				APIKey:    "test",
+				Model:     "test-model",
+				Reasoning: ReasoningMedium,
+			})
+			if err != nil {
+				t.Fatalf("new client: %v", err)
+			}
+
+			var streamed strings.Builder
+			resp, err := client.Stream(context.Background(), []Message{{Role: "user", Content: "hello"}}, func(delta string) {
+				streamed.WriteString(delta)
+			})
+			if err != nil {
+				t.Fatalf("stream: %v", err)
+			}
+
+			if resp.Text != "你好" || streamed.String() != "你好" {
+				t.Errorf("text = %q, streamed = %q, want %q", resp.Text, streamed.String(), "你好")
+			}
+			if resp.Reasoning != "thinking" {
+				t.Errorf("reasoning = %q, want %q", resp.Reasoning, "thinking")
+			}
+		})
+	}
+}
+
+func TestOpenAIReasoningRequest(t *testing.T) {
+	// The request's own limits: 1000 tokens at temperature 0.3.
+	const maxTokens, temperature = 1000, 0.3
+
+	tests := []struct {
+		model      string
+		level      string
+		wantEffort string
+		reasoning  bool // whether max_completion_tokens replaces max_tokens and temperature
+	}{
+		{"gpt-5", "", "", true},
+		{"gpt-5", ReasoningOff, "minimal", true},
+		{"gpt-5-mini", ReasoningHigh, "high", true},
+		{"openai/gpt-5", ReasoningOff, "minimal", true},
+		{"gpt-5-chat-latest", ReasoningOff, "", false},
+		{"o3-mini", ReasoningOff, "", true},
+		{"o1", ReasoningLow, "low", true},
+		{"o4-mini", "", "", true},
+		{"gpt-4o", ReasoningOff, "", false},
+		{"deepseek-chat", ReasoningOff, "", false},
+		{"deepseek-reasoner", ReasoningHigh, "", false},
+		{"gpt-4o", ReasoningMedium, "", false},
+	}
+
+	for _, tt := range tests {
+		o := &openaiProvider{provider: &types.Provider{
+			Model:       tt.model,
+			MaxTokens:   maxTokens,
+			Temperature: temperature,
+			Reasoning:   tt.level,
+		}}
+		req := o.newOpenAIRequest(nil)
+
+		if req.ReasoningEffort != tt.wantEffort {
+			t.Errorf("%s %q: reasoning_effort = %q, want %q", tt.model, tt.level, req.ReasoningEffort, tt.wantEffort)
+		}
+		if !tt.reasoning {
+			if req.MaxTokens != maxTokens || req.Temperature != temperature || req.MaxCompletionTokens != 0 {
+				t.Errorf("%s %q: max_tokens = %d, temperature = %v, max_completion_tokens = %d",
+					tt.model, tt.level, req.MaxTokens, req.Temperature, req.MaxCompletionTokens)
+			}
+			continue
+		}
+		if req.MaxTokens != 0 || req.Temperature != 0 {
+			t.Errorf("%s %q: max_tokens and temperature must be omitted for reasoning models", tt.model, tt.level)
+		}
+		if want := maxTokens + reasoningBudget(tt.level); req.MaxCompletionTokens != want {
+			t.Errorf("%s %q: max_completion_tokens = %d, want %d", tt.model, tt.level, req.MaxCompletionTokens, want)
+		}
+	}
+}
+
+func TestGeminiReasoningRequest(t *testing.T) {
+	const maxTokens = 1000
+
+	tests := []struct {
+		model           string
+		level           string
+		disableThinking bool
+		wantBudget      int // -1 for no thinkingConfig
+	}{
+		{"gemini-2.5-flash", ReasoningOff, false, 0},
+		{"gemini-2.5-flash", "", true, 0},
+		{"gemini-2.5-flash-lite", ReasoningLow, false, 1024},
+		{"models/gemini-2.5-flash", ReasoningHigh, false, 16384},
+		{"gemini-flash-latest", ReasoningOff, false, 0},
+		{"gemini-2.5-flash", "", false, -1},
+		{"gemini-2.5-pro", ReasoningOff, false, 128},
+		{"gemini-2.5-pro", "", true, 128},
+		{"gemini-2.5-pro", ReasoningMedium, false, 4096},
+		{"gemini-2.0-flash", ReasoningOff, false, -1},
+		{"gemini-2.0-flash", ReasoningHigh, false, -1},
+		{"gemini-1.5-pro", "", true, -1},
+		{"gemma-3-27b-it", ReasoningLow, false, -1},
+	}
+
+	for _, tt := range tests {
+		g := &geminiProvider{provider: &types.Provider{
+			Model:           tt.model,
+			MaxTokens:       maxTokens,
+			Reasoning:       tt.level,
+			DisableThinking: tt.disableThinking,
+		}}
+		cfg := g.newGeminiRequest(nil).GenerationConfig
+
+		if tt.wantBudget < 0 {
+			if cfg.ThinkingConfig != nil {
+				t.Errorf("%s %q: thinkingConfig = %+v, want none", tt.model, tt.level, *cfg.ThinkingConfig)
+			}
+			if cfg.MaxOutputTokens != maxTokens {
+				t.Errorf("%s %q: maxOutputTokens = %d, want %d", tt.model, tt.level, cfg.MaxOutputTokens, maxTokens)
+			}
+			continue
+		}
+		if cfg.ThinkingConfig == nil {
+			t.Errorf("%s %q: no thinkingConfig", tt.model, tt.level)
+			continue
+		}
+		if cfg.ThinkingConfig.ThinkingBudget != tt.wantBudget {
+			t.Errorf("%s %q: thinkingBudget = %d, want %d", tt.model, tt.level, cfg.ThinkingConfig.ThinkingBudget, tt.wantBudget)
+		}
+		if want := maxTokens + tt.wantBudget; cfg.MaxOutputTokens != want {
+			t.Errorf("%s %q: maxOutputTokens = %d, want %d", tt.model, tt.level, cfg.MaxOutputTokens, want)
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"time"
+)
+
+// RetryPolicy controls how retryable failures are retried.
+type RetryPolicy struct {
+	MaxRetries int           // retries after the first attempt
+	BaseDelay  time.Duration // delay before the first retry, doubled per attempt
+	MaxDelay   time.Duration // upper bound for backoff and for honouring Retry-After
+}
+
+// DefaultRetryPolicy is used by clients created with NewClient.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxRetries: 3,
+	BaseDelay:  500 * time.Millisecond,
+	MaxDelay:   10 * time.Second,
+}
+
+// do calls fn until it succeeds, fails with an error that is not retryable,
+// or the retry budget is exhausted.
+func (p RetryPolicy) do(ctx context.Context, fn func() error) error {
+	for attempt := 0; ; attempt++ {
+		err := fn()
+		if err == nil {
+			return nil
+		}
+
+		var perm *permanentError
+		if errors.As(err, &perm) {
+			return perm.err
+		}
+
+		var apiErr *Error
+		if !errors.As(err, &apiErr) || !apiErr.Retryable() || attempt >= p.MaxRetries {
+			return err
+		}
+
+		// Don't block for longer than we're willing to wait; let the caller
+		// decide (e.g. fail over to another provider) instead.
+		if apiErr.RetryAfter > p.MaxDelay {
+			return err
+		}
+
+		timer := time.NewTimer(p.backoff(attempt, apiErr.RetryAfter))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+	}
+}
+
+// backoff returns the delay before retry number attempt (0-based).
+// A server-requested delay takes precedence over jittered exponential backoff.
+func (p RetryPolicy) backoff(attempt int, retryAfter time.Duration) time.Duration {
+	if retryAfter > 0 {
+		return retryAfter
+	}
+
+	d := p.BaseDelay << attempt
+	if d <= 0 || d > p.MaxDelay {
+		d = p.MaxDelay
+	}
+	if d <= 0 {
+		return 0
+	}
+
+	// Equal jitter: half fixed, half random, so retries never fire immediately.
+	return d/2 + rand.N(d/2+1)
+}
+
+// permanentError marks an error as not retryable regardless of its code.
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string { return e.err.Error() }
+func (e *permanentError) Unwrap() error { return e.err }
+
+func permanent(err error) error {
+	return &permanentError{err: err}
+}
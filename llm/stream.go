@@ -0,0 +1,90 @@
+package llm
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// maxSSELineSize bounds a single SSE line; large enough for long JSON chunks.
+const maxSSELineSize = 1 << 20 // 1 MiB
+
+// StreamFunc receives incremental text as it arrives from the provider.
+type StreamFunc func(delta string)
+
+// sseEvent is a single Server-Sent Event.
+type sseEvent struct {
+	Event string
+	Data  string
+}
+
+// readSSE parses a Server-Sent Events stream and calls fn for every event.
+// Returning errStopStream from fn ends parsing without error.
+func readSSE(r io.Reader, fn func(ev sseEvent) error) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxSSELineSize)
+
+	var ev sseEvent
+	var data strings.Builder
+
+	dispatch := func() error {
+		if data.Len() == 0 && ev.Event == "" {
+			return nil
+		}
+		ev.Data = data.String()
+		err := fn(ev)
+		ev = sseEvent{}
+		data.Reset()
+		return err
+	}
+
+	for scanner.Scan() {
+		line := scanner.Bytes()
+
+		// Blank line terminates the current event.
+		if len(line) == 0 {
+			if err := dispatch(); err != nil {
+				return stopOrErr(err)
+			}
+			continue
+		}
+
+		// Lines starting with ':' are comments (keep-alives).
+		if line[0] == ':' {
+			continue
+		}
+
+		field, value, _ := bytes.Cut(line, []byte(":"))
+		value = bytes.TrimPrefix(value, []byte(" "))
+
+		switch string(field) {
+		case "event":
+			ev.Event = string(value)
+		case "data":
+			if data.Len() > 0 {
+				data.WriteByte('\n')
+			}
+			data.Write(value)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("read stream: %w", err)
+	}
+
+	// Flush a trailing event without a terminating blank line.
+	return stopOrErr(dispatch())
+}
+
+// errStopStream signals readSSE to stop reading without reporting an error.
+var errStopStream = errors.New("stop stream")
+
+func stopOrErr(err error) error {
+	if errors.Is(err, errStopStream) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,195 @@
+package llm
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go.aimuz.me/transy/internal/types"
+)
+
+func TestReadSSE(t *testing.T) {
+	input := ": keep-alive\n" +
+		"event: first\n" +
+		"data: a\n" +
+		"data: b\n" +
+		"\n" +
+		"data: c\n" +
+		"\n" +
+		"data: trailing"
+
+	var got []sseEvent
+	err := readSSE(strings.NewReader(input), func(ev sseEvent) error {
+		got = append(got, ev)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("readSSE: %v", err)
+	}
+
+	want := []sseEvent{
+		{Event: "first", Data: "a\nb"},
+		{Data: "c"},
+		{Data: "trailing"},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %d events, want %d: %+v", len(got), len(want), got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("event %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestStream(t *testing.T) {
+	tests := []struct {
+		name      string
+		typ       string
+		body      string
+		wantUsage types.Usage
+	}{
+		{
+			name: "openai",
+			typ:  "openai-compatible",
+			body: `data: {"choices":[{"delta":{"content":"你"}}]}` + "\n\n" +
+				`data: {"choices":[{"delta":{"content":"好"}}]}` + "\n\n" +
+				`data: {"choices":[],"usage":{"prompt_tokens":3,"completion_tokens":2,"total_tokens":5}}` + "\n\n" +
+				"data: [DONE]\n\n",
+			wantUsage: types.Usage{PromptTokens: 3, CompletionTokens: 2, TotalTokens: 5},
+		},
+		{
+			name: "claude",
+			typ:  "claude",
+			body: "event: message_start\n" +
+				`data: {"type":"message_start","message":{"usage":{"input_tokens":3,"output_tokens":1}}}` + "\n\n" +
+				"event: content_block_delta\n" +
+				`data: {"type":"content_block_delta","delta":{"type":"text_delta","text":"你"}}` + "\n\n" +
+				"event: content_block_delta\n" +
+				`data: {"type":"content_block_delta","delta":{"type":"text_delta","text":"好"}}` + "\n\n" +
+				"event: message_delta\n" +
+				`data: {"type":"message_delta","usage":{"output_tokens":2}}` + "\n\n" +
+				"event: message_stop\n" +
+				`data: {"type":"message_stop"}` + "\n\n",
+			wantUsage: types.Usage{PromptTokens: 3, CompletionTokens: 2, TotalTokens: 5},
+		},
+		{
+			name: "gemini",
+			typ:  "gemini",
+			body: `data: {"candidates":[{"content":{"parts":[{"text":"你"}]}}]}` + "\n\n" +
+				`data: {"candidates":[{"content":{"parts":[{"text":"好"}]},"finishReason":"STOP"}],"usageMetadata":{"promptTokenCount":3,"candidatesTokenCount":2,"totalTokenCount":5}}` + "\n\n",
+			wantUsage: types.Usage{PromptTokens: 3, CompletionTokens: 2, TotalTokens: 5},
+		},
+		{
+			name: "ollama",
+			typ:  "ollama",
+			body: `{"message":{"content":"你"},"done":false}` + "\n" +
+				`{"message":{"content":"好"},"done":false}` + "\n" +
+				`{"message":{"content":""},"done":true,"prompt_eval_count":3,"eval_count":2}` + "\n",
+			wantUsage: types.Usage{PromptTokens: 3, CompletionTokens: 2, TotalTokens: 5},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "text/event-stream")
+				_, _ = w.Write([]byte(tt.body))
+			}))
+			defer srv.Close()
+
+			client, err := NewClient(&types.Provider{
+				Type:    tt.typ,
+				BaseURL: srv.URL,
+				APIKey:  "test",
+				Model:   "test-model",
+			})
+			if err != nil {
+				t.Fatalf("new client: %v", err)
+			}
+
+			var deltas []string
+			resp, err := client.Stream(context.Background(), []Message{{Role: "user", Content: "hello"}}, func(delta string) {
+				deltas = append(deltas, delta)
+			})
+			if err != nil {
+				t.Fatalf("stream: %v", err)
+			}
+
+			if resp.Text != "你好" {
+				t.Errorf("text = %q, want %q", resp.Text, "你好")
+			}
+			if len(deltas) != 2 {
+				t.Errorf("deltas = %q, want 2 chunks", deltas)
+			}
+			if resp.Usage != tt.wantUsage {
+				t.Errorf("usage = %+v, want %+v", resp.Usage, tt.wantUsage)
+			}
+		})
+	}
+}
+
+func TestStreamTruncated(t *testing.T) {
+	// Each body stops where a dropped connection might cut it off, before
+	// the provider marks the response complete.
+	tests := []struct {
+		name string
+		typ  string
+		body string
+	}{
+		{
+			name: "openai",
+			typ:  "openai-compatible",
+			body: `data: {"choices":[{"delta":{"content":"你"}}]}` + "\n\n",
+		},
+		{
+			name: "claude",
+			typ:  "claude",
+			body: "event: content_block_delta\n" +
+				`data: {"type":"content_block_delta","delta":{"type":"text_delta","text":"你"}}` + "\n\n",
+		},
+		{
+			name: "gemini",
+			typ:  "gemini",
+			body: `data: {"candidates":[{"content":{"parts":[{"text":"你"}]}}]}` + "\n\n",
+		},
+		{
+			name: "ollama",
+			typ:  "ollama",
+			body: `{"message":{"content":"你"},"done":false}` + "\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "text/event-stream")
+				_, _ = w.Write([]byte(tt.body))
+			}))
+			defer srv.Close()
+
+			client, err := NewClient(&types.Provider{
+				Type:    tt.typ,
+				BaseURL: srv.URL,
+				APIKey:  "test",
+				Model:   "test-model",
+			})
+			if err != nil {
+				t.Fatalf("new client: %v", err)
+			}
+
+			resp, err := client.Stream(context.Background(), []Message{{Role: "user", Content: "hello"}}, func(string) {})
+			if CodeOf(err) != CodeTransient {
+				t.Fatalf("err = %v, want a transient error", err)
+			}
+			if resp.Text != "" {
+				t.Errorf("partial text returned: %q", resp.Text)
+			}
+			if !Failover(err) {
+				t.Error("truncated stream should fail over")
+			}
+		})
+	}
+}
@@ -0,0 +1,75 @@
+package llm
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// instrumentationName identifies this package in traces and metrics.
+const instrumentationName = "go.aimuz.me/transy/llm"
+
+// Attribute names follow the OpenTelemetry semantic conventions for
+// generative AI clients where they exist.
+var (
+	tracer = otel.Tracer(instrumentationName)
+	meter  = otel.Meter(instrumentationName)
+
+	operationDuration, _ = meter.Float64Histogram("gen_ai.client.operation.duration",
+		metric.WithUnit("s"),
+		metric.WithDescription("Duration of LLM calls, including retries"))
+	tokenUsage, _ = meter.Int64Histogram("gen_ai.client.token.usage",
+		metric.WithUnit("{token}"),
+		metric.WithDescription("Tokens used per LLM call"))
+)
+
+// startSpan starts the span of a chat call to the client's provider.
+func (c *Client) startSpan(ctx context.Context, stream bool) (context.Context, trace.Span) {
+	return tracer.Start(ctx, "chat "+c.provider.Model,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(append(c.attributes(),
+			attribute.Bool("transy.stream", stream),
+		)...),
+	)
+}
+
+// endSpan records the outcome of a call started at start on its span and in
+// the metrics, then ends the span.
+func (c *Client) endSpan(ctx context.Context, span trace.Span, start time.Time, resp Response, err error) {
+	latency := time.Since(start)
+	attrs := c.attributes()
+	if err != nil {
+		attrs = append(attrs, attribute.String("error.type", string(CodeOf(err))))
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	operationDuration.Record(ctx, latency.Seconds(), metric.WithAttributes(attrs...))
+
+	if err == nil {
+		span.SetAttributes(
+			attribute.Int("gen_ai.usage.input_tokens", resp.Usage.PromptTokens),
+			attribute.Int("gen_ai.usage.output_tokens", resp.Usage.CompletionTokens),
+		)
+		tokenUsage.Record(ctx, int64(resp.Usage.PromptTokens),
+			metric.WithAttributes(append(attrs, attribute.String("gen_ai.token.type", "input"))...))
+		tokenUsage.Record(ctx, int64(resp.Usage.CompletionTokens),
+			metric.WithAttributes(append(attrs, attribute.String("gen_ai.token.type", "output"))...))
+	}
+	span.SetAttributes(attribute.Int64("transy.latency_ms", latency.Milliseconds()))
+	span.End()
+}
+
+// attributes identify the client's provider and model.
+func (c *Client) attributes() []attribute.KeyValue {
+	return []attribute.KeyValue{
+		attribute.String("gen_ai.operation.name", "chat"),
+		attribute.String("gen_ai.system", c.provider.Type),
+		attribute.String("gen_ai.request.model", c.provider.Model),
+		attribute.String("transy.provider", c.provider.Name),
+	}
+}
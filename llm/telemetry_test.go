@@ -0,0 +1,69 @@
+package llm
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestCompleteSpan(t *testing.T) {
+	spans := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(spans))
+	otel.SetTracerProvider(tp)
+	defer tp.Shutdown(context.Background())
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Fail") != "" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_, _ = w.Write([]byte(`{"choices":[{"message":{"content":"ok"}}],"usage":{"prompt_tokens":3,"completion_tokens":2,"total_tokens":5}}`))
+	}))
+	defer srv.Close()
+
+	client := newTestClient(t, srv.URL)
+	if _, err := client.Complete(context.Background(), []Message{{Role: "user", Content: "hi"}}); err != nil {
+		t.Fatalf("complete: %v", err)
+	}
+	client.provider.Headers = map[string]string{"X-Fail": "1"}
+	if _, err := client.Complete(context.Background(), []Message{{Role: "user", Content: "hi"}}); err == nil {
+		t.Fatal("expected error")
+	}
+
+	got := spans.GetSpans()
+	if len(got) != 2 {
+		t.Fatalf("got %d spans, want 2", len(got))
+	}
+
+	ok, failed := got[0], got[1]
+	if ok.Name != "chat test-model" {
+		t.Errorf("span name = %q", ok.Name)
+	}
+	attrs := make(map[string]any)
+	for _, kv := range ok.Attributes {
+		attrs[string(kv.Key)] = kv.Value.AsInterface()
+	}
+	for key, want := range map[string]any{
+		"gen_ai.system":              "openai-compatible",
+		"gen_ai.request.model":       "test-model",
+		"gen_ai.usage.input_tokens":  int64(3),
+		"gen_ai.usage.output_tokens": int64(2),
+	} {
+		if attrs[key] != want {
+			t.Errorf("%s = %v, want %v", key, attrs[key], want)
+		}
+	}
+	if _, ok := attrs["transy.latency_ms"]; !ok {
+		t.Error("latency attribute missing")
+	}
+
+	if failed.Status.Code != codes.Error {
+		t.Errorf("failed call status = %v, want error", failed.Status.Code)
+	}
+}
@@ -0,0 +1,131 @@
+package llm
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+
+	"go.aimuz.me/transy/internal/types"
+)
+
+// Connection reuse settings. Translations are often seconds or minutes
+// apart, so idle connections are kept longer than the default 90s, and
+// idle HTTP/2 connections are pinged so that a dead one is noticed before
+// a request is sent on it.
+const (
+	idleConnTimeout     = 5 * time.Minute
+	maxIdleConnsPerHost = 4
+	http2PingInterval   = 30 * time.Second
+	http2PingTimeout    = 10 * time.Second
+)
+
+// newHTTPClient builds the HTTP client for a provider from its network
+// settings: proxy, custom CA bundle, client certificate and timeouts.
+func newHTTPClient(p *types.Provider) (*http.Client, error) {
+	tr := http.DefaultTransport.(*http.Transport).Clone()
+	tr.ForceAttemptHTTP2 = true
+	tr.IdleConnTimeout = idleConnTimeout
+	tr.MaxIdleConnsPerHost = maxIdleConnsPerHost
+	tr.HTTP2 = &http.HTTP2Config{
+		SendPingTimeout: http2PingInterval,
+		PingTimeout:     http2PingTimeout,
+	}
+
+	if p.Proxy != "" {
+		u, err := parseProxy(p.Proxy)
+		if err != nil {
+			return nil, err
+		}
+		tr.Proxy = http.ProxyURL(u)
+	}
+
+	if p.CACertFile != "" || p.ClientCertFile != "" {
+		cfg, err := tlsConfig(p)
+		if err != nil {
+			return nil, err
+		}
+		tr.TLSClientConfig = cfg
+	}
+
+	// Resume TLS sessions when a connection has to be re-established.
+	if tr.TLSClientConfig == nil {
+		tr.TLSClientConfig = &tls.Config{}
+	}
+	tr.TLSClientConfig.ClientSessionCache = tls.NewLRUClientSessionCache(0)
+
+	if p.RequestTimeout > 0 {
+		// Bounds the wait for each attempt's response headers only, so long
+		// streamed responses are not cut off once they have started.
+		tr.ResponseHeaderTimeout = time.Duration(p.RequestTimeout) * time.Second
+	}
+
+	return &http.Client{Transport: &debugTransport{next: tr, provider: p.Name, secret: p.APIKey}}, nil
+}
+
+// parseProxy parses an HTTP, HTTPS or SOCKS5 proxy URL.
+func parseProxy(s string) (*url.URL, error) {
+	u, err := url.Parse(s)
+	if err != nil {
+		return nil, fmt.Errorf("parse proxy url: %w", err)
+	}
+	switch u.Scheme {
+	case "http", "https", "socks5", "socks5h":
+	default:
+		return nil, fmt.Errorf("unsupported proxy scheme %q: want http, https or socks5", u.Scheme)
+	}
+	if u.Host == "" {
+		return nil, fmt.Errorf("proxy url %q has no host", s)
+	}
+	return u, nil
+}
+
+// tlsConfig trusts the provider's CA bundle in addition to the system roots
+// and presents its client certificate for mutual TLS.
+func tlsConfig(p *types.Provider) (*tls.Config, error) {
+	cfg := &tls.Config{MinVersion: tls.VersionTLS12}
+
+	if p.CACertFile != "" {
+		pem, err := os.ReadFile(p.CACertFile)
+		if err != nil {
+			return nil, fmt.Errorf("read ca bundle: %w", err)
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in %s", p.CACertFile)
+		}
+		cfg.RootCAs = pool
+	}
+
+	if p.ClientCertFile != "" {
+		cert, err := tls.LoadX509KeyPair(p.ClientCertFile, p.ClientKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("load client certificate: %w", err)
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+
+	return cfg, nil
+}
+
+// validateNetwork checks the network settings shared by all backends.
+func validateNetwork(p *types.Provider) error {
+	if p.Proxy != "" {
+		if _, err := parseProxy(p.Proxy); err != nil {
+			return err
+		}
+	}
+	if (p.ClientCertFile == "") != (p.ClientKeyFile == "") {
+		return fmt.Errorf("client certificate and key must be set together")
+	}
+	if p.RequestTimeout < 0 || p.Timeout < 0 {
+		return fmt.Errorf("timeouts must not be negative")
+	}
+	return nil
+}
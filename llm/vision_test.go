@@ -0,0 +1,90 @@
+package llm
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go.aimuz.me/transy/internal/types"
+)
+
+func TestImageEncoding(t *testing.T) {
+	// "png" in base64
+	const data = "cG5n"
+
+	tests := []struct {
+		name  string
+		typ   string
+		reply string
+		want  []string // fragments expected in the request body
+	}{
+		{
+			name:  "openai image_url",
+			typ:   "openai-compatible",
+			reply: `{"choices":[{"message":{"content":"ok"}}]}`,
+			want: []string{
+				`"content":[{"type":"text","text":"translate"}`,
+				`{"type":"image_url","image_url":{"url":"data:image/png;base64,` + data + `"}}`,
+			},
+		},
+		{
+			name:  "claude image block",
+			typ:   "claude",
+			reply: `{"content":[{"type":"text","text":"ok"}]}`,
+			want: []string{
+				`{"type":"image","source":{"type":"base64","media_type":"image/png","data":"` + data + `"}}`,
+				`{"type":"text","text":"translate"}`,
+			},
+		},
+		{
+			name:  "gemini inlineData",
+			typ:   "gemini",
+			reply: `{"candidates":[{"content":{"parts":[{"text":"ok"}]}}]}`,
+			want: []string{
+				`{"text":"translate"}`,
+				`{"inlineData":{"mimeType":"image/png","data":"` + data + `"}}`,
+			},
+		},
+		{
+			name:  "ollama images",
+			typ:   "ollama",
+			reply: `{"message":{"content":"ok"},"done":true}`,
+			want:  []string{`"images":["` + data + `"]`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var body string
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				b, _ := io.ReadAll(r.Body)
+				body = string(b)
+				_, _ = w.Write([]byte(tt.reply))
+			}))
+			defer srv.Close()
+
+			client, err := NewClient(&types.Provider{Type: tt.typ, BaseURL: srv.URL, APIKey: "k", Model: "m"})
+			if err != nil {
+				t.Fatalf("new client: %v", err)
+			}
+
+			messages := []Message{{
+				Role:    "user",
+				Content: "translate",
+				Images:  []Image{{MIMEType: "image/png", Data: []byte("png")}},
+			}}
+			if _, err := client.Complete(context.Background(), messages); err != nil {
+				t.Fatalf("complete: %v", err)
+			}
+
+			for _, frag := range tt.want {
+				if !strings.Contains(body, frag) {
+					t.Errorf("request body missing %s\nbody: %s", frag, body)
+				}
+			}
+		})
+	}
+}
@@ -2,12 +2,22 @@ package main
 
 import (
 	"context"
+	"crypto/rand"
+	"crypto/sha256"
 	"embed"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
 	"fmt"
 	"log/slog"
+	"maps"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
 	"time"
+	"unicode/utf8"
 
 	"github.com/wailsapp/wails/v2"
 	"github.com/wailsapp/wails/v2/pkg/options"
@@ -17,12 +27,23 @@ import (
 	"go.aimuz.me/transy/cache"
 	"go.aimuz.me/transy/clipboard"
 	"go.aimuz.me/transy/config"
+	"go.aimuz.me/transy/glossary"
 	"go.aimuz.me/transy/hotkey"
+	"go.aimuz.me/transy/internal/logfile"
 	"go.aimuz.me/transy/internal/types"
 	"go.aimuz.me/transy/langdetect"
 	"go.aimuz.me/transy/llm"
 	"go.aimuz.me/transy/ocr"
+	"go.aimuz.me/transy/prompt"
+	"go.aimuz.me/transy/quality"
 	"go.aimuz.me/transy/screenshot"
+	"go.aimuz.me/transy/telemetry"
+	"go.aimuz.me/transy/tm"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
 )
 
 //go:embed all:frontend/dist
@@ -30,14 +51,29 @@ var assets embed.FS
 
 // App is the main application struct bound to Wails.
 type App struct {
-	ctx    context.Context
-	cfg    *config.Config
-	hotkey *hotkey.HotkeyManager
-	cache  *cache.Cache
+	ctx      context.Context
+	cfg      *config.Config
+	hotkey   *hotkey.HotkeyManager
+	cache    *cache.Cache
+	glossary *glossary.Store
+	memory   *tm.Memory
+	clients  *llm.Pool
+
+	shutdownTelemetry func(context.Context) error
+
+	mu       sync.Mutex
+	inflight map[string]context.CancelFunc  // in-flight translations by request ID
+	latest   string                         // ID of the most recent translation
+	sessions map[string]*translationSession // refinable translations by session ID
+	debugLog *logfile.File                  // nil while debug logging is off
 }
 
 func NewApp() *App {
-	return &App{}
+	return &App{
+		clients:  llm.NewPool(),
+		inflight: make(map[string]context.CancelFunc),
+		sessions: make(map[string]*translationSession),
+	}
 }
 
 // ─────────────────────────────────────────────────────────────────────────────
@@ -54,13 +90,27 @@ func (a *App) startup(ctx context.Context) {
 	}
 	a.cfg = cfg
 
+	a.setupTelemetry()
+	if a.cfg.DebugLog {
+		if err := a.openDebugLog(); err != nil {
+			slog.Error("init debug log", "error", err)
+		}
+	}
+
 	// Initialize cache
 	a.setupCache()
+	a.setupGlossary()
+	a.setupMemory()
 
 	a.setupHotkey()
+
+	go a.warmUp()
 }
 
 func (a *App) shutdown(_ context.Context) {
+	a.cancelAllTranslations()
+	a.clients.Close()
+
 	if a.hotkey != nil {
 		a.hotkey.Stop()
 	}
@@ -69,6 +119,19 @@ func (a *App) shutdown(_ context.Context) {
 			slog.Error("close cache", "error", err)
 		}
 	}
+	if a.memory != nil {
+		if err := a.memory.Close(); err != nil {
+			slog.Error("close translation memory", "error", err)
+		}
+	}
+	a.closeDebugLog()
+	if a.shutdownTelemetry != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), telemetryShutdownTimeout)
+		defer cancel()
+		if err := a.shutdownTelemetry(ctx); err != nil {
+			slog.Error("shutdown telemetry", "error", err)
+		}
+	}
 }
 
 func (a *App) setupCache() {
@@ -79,7 +142,10 @@ func (a *App) setupCache() {
 	}
 
 	cachePath := filepath.Join(configDir, "transy", "cache")
-	c, err := cache.New(cachePath)
+	c, err := cache.New(cachePath,
+		cache.WithMaxEntries(a.cfg.Cache.MaxEntries),
+		cache.WithMaxSize(int64(a.cfg.Cache.MaxSizeMB)<<20),
+	)
 	if err != nil {
 		slog.Error("init cache", "error", err)
 		return
@@ -88,6 +154,59 @@ func (a *App) setupCache() {
 	slog.Info("cache initialized", "path", cachePath)
 }
 
+func (a *App) setupGlossary() {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		slog.Error("get config dir for glossary", "error", err)
+		return
+	}
+
+	g, err := glossary.Open(filepath.Join(configDir, "transy", "glossary.json"))
+	if err != nil {
+		slog.Error("init glossary", "error", err)
+		return
+	}
+	a.glossary = g
+}
+
+func (a *App) setupMemory() {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		slog.Error("get config dir for translation memory", "error", err)
+		return
+	}
+
+	memoryPath := filepath.Join(configDir, "transy", "memory")
+	m, err := tm.Open(memoryPath)
+	if err != nil {
+		slog.Error("init translation memory", "error", err)
+		return
+	}
+	a.memory = m
+	slog.Info("translation memory initialized", "path", memoryPath)
+}
+
+// telemetryShutdownTimeout bounds the flush of pending telemetry on exit.
+const telemetryShutdownTimeout = 5 * time.Second
+
+func (a *App) setupTelemetry() {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		slog.Error("get config dir for telemetry", "error", err)
+		return
+	}
+
+	shutdown, err := telemetry.Setup(a.ctx, a.cfg.Telemetry, filepath.Join(configDir, "transy", "telemetry.jsonl"))
+	if err != nil {
+		slog.Error("init telemetry", "error", err)
+		return
+	}
+	a.shutdownTelemetry = shutdown
+	if a.cfg.Telemetry.Exporter != telemetry.ExporterNone {
+		slog.Info("telemetry initialized", "exporter", a.cfg.Telemetry.Exporter)
+	}
+}
+
 func (a *App) setupHotkey() {
 	a.hotkey = hotkey.NewHotkeyManager(
 		func() {
@@ -118,15 +237,26 @@ func (a *App) setupHotkey() {
 }
 
 // TakeScreenshotAndOCR captures a screenshot and performs OCR.
-// Returns the recognized text.
-func (a *App) TakeScreenshotAndOCR() (string, error) {
+// Returns the recognized text. If the active provider translates
+// screenshots with vision instead, the image is sent to the frontend in a
+// "screenshot-image" event to be translated as is, and "" is returned.
+func (a *App) TakeScreenshotAndOCR() (text string, err error) {
+	ctx, span := tracer.Start(a.ctx, "TakeScreenshotAndOCR")
+	defer func(start time.Time) {
+		span.SetAttributes(attribute.Int("transy.text_length", utf8.RuneCountInString(text)))
+		endSpan(span, start, err)
+	}(time.Now())
+
 	// Hide window to allow capturing screen behind it
 	runtime.WindowHide(a.ctx)
 
 	// Give a little time for window to hide
 	time.Sleep(100 * time.Millisecond)
 
+	_, captureSpan := tracer.Start(ctx, "screenshot.capture")
+	captureStart := time.Now()
 	imagePath, err := screenshot.CaptureInteractive()
+	endSpan(captureSpan, captureStart, err)
 	if err != nil {
 		// If cancelled or failed, show window again if not active
 		runtime.WindowShow(a.ctx)
@@ -134,7 +264,20 @@ func (a *App) TakeScreenshotAndOCR() (string, error) {
 	}
 	defer os.Remove(imagePath) // Clean up temp file
 
-	text, err := ocr.RecognizeText(imagePath)
+	if p := a.GetActiveProvider(); p != nil && p.ScreenshotMode == types.ScreenshotModeVision {
+		data, err := os.ReadFile(imagePath)
+		runtime.WindowShow(a.ctx)
+		if err != nil {
+			return "", fmt.Errorf("read screenshot: %w", err)
+		}
+		runtime.EventsEmit(a.ctx, "screenshot-image", base64.StdEncoding.EncodeToString(data))
+		return "", nil
+	}
+
+	_, ocrSpan := tracer.Start(ctx, "ocr.recognize")
+	ocrStart := time.Now()
+	text, err = ocr.RecognizeText(imagePath)
+	endSpan(ocrSpan, ocrStart, err)
 	if err != nil {
 		runtime.WindowShow(a.ctx)
 		return "", fmt.Errorf("recognize text: %w", err)
@@ -156,6 +299,7 @@ func (a *App) TakeScreenshotAndOCR() (string, error) {
 
 func (a *App) ToggleWindowVisibility() {
 	runtime.WindowShow(a.ctx)
+	go a.warmUp()
 
 	text, err := clipboard.GetText(a.ctx)
 	if err != nil {
@@ -167,6 +311,28 @@ func (a *App) ToggleWindowVisibility() {
 	}
 }
 
+// warmUpTimeout bounds a connection warm-up.
+const warmUpTimeout = 10 * time.Second
+
+// warmUp connects to the active provider in the background, so that the
+// translation the user is about to request doesn't wait for the handshakes.
+func (a *App) warmUp() {
+	p := a.cfg.GetActiveProvider()
+	if p == nil {
+		return
+	}
+	client, err := a.clients.Get(p)
+	if err != nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(a.ctx, warmUpTimeout)
+	defer cancel()
+	if err := client.Warm(ctx); err != nil {
+		slog.Debug("warm up provider", "provider", p.Name, "error", err)
+	}
+}
+
 func (a *App) GetAccessibilityPermission() bool {
 	return hotkey.IsAccessibilityEnabled(false)
 }
@@ -183,12 +349,21 @@ func (a *App) AddProvider(p types.Provider) error {
 	return a.cfg.AddProvider(p)
 }
 
+// UpdateProvider updates a provider, rebuilding its client on next use.
 func (a *App) UpdateProvider(name string, p types.Provider) error {
-	return a.cfg.UpdateProvider(name, p)
+	if err := a.cfg.UpdateProvider(name, p); err != nil {
+		return err
+	}
+	a.clients.Invalidate(name)
+	return nil
 }
 
 func (a *App) RemoveProvider(name string) error {
-	return a.cfg.RemoveProvider(name)
+	if err := a.cfg.RemoveProvider(name); err != nil {
+		return err
+	}
+	a.clients.Invalidate(name)
+	return nil
 }
 
 func (a *App) SetProviderActive(name string) error {
@@ -199,6 +374,447 @@ func (a *App) GetActiveProvider() *types.Provider {
 	return a.cfg.GetActiveProvider()
 }
 
+// GetFallbacks returns the ordered names of the failover providers.
+func (a *App) GetFallbacks() []string {
+	return a.cfg.Fallbacks
+}
+
+// SetFallbacks sets the ordered names of the failover providers.
+func (a *App) SetFallbacks(names []string) error {
+	return a.cfg.SetFallbacks(names)
+}
+
+// ListModels returns the models available from a provider configuration,
+// e.g. the models pulled into a local Ollama daemon.
+func (a *App) ListModels(p types.Provider) ([]string, error) {
+	client, err := llm.NewClient(&p)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(a.ctx, 10*time.Second)
+	defer cancel()
+
+	return client.ListModels(ctx)
+}
+
+// GetProviderTypes returns the registered provider types and their capabilities.
+func (a *App) GetProviderTypes() []llm.TypeInfo {
+	return llm.Types()
+}
+
+// ─────────────────────────────────────────────────────────────────────────────
+// Prompt Templates
+// ─────────────────────────────────────────────────────────────────────────────
+
+// GetPromptTemplates returns the configured prompt templates, preceded by
+// the built-in default unless a configured template replaces it.
+func (a *App) GetPromptTemplates() []prompt.Template {
+	templates := a.cfg.PromptTemplates
+	if !slices.ContainsFunc(templates, func(t prompt.Template) bool { return t.ID == prompt.DefaultID }) {
+		templates = append([]prompt.Template{prompt.Default}, templates...)
+	}
+	return templates
+}
+
+// SavePromptTemplate adds a prompt template or replaces the one with its ID.
+func (a *App) SavePromptTemplate(t prompt.Template) error {
+	return a.cfg.SavePromptTemplate(t)
+}
+
+// RemovePromptTemplate removes the prompt template with the given ID.
+func (a *App) RemovePromptTemplate(id string) error {
+	return a.cfg.RemovePromptTemplate(id)
+}
+
+// GetPromptRules returns the rules selecting templates by language pair.
+func (a *App) GetPromptRules() []prompt.Rule {
+	return a.cfg.PromptRules
+}
+
+// SetPromptRules replaces the rules selecting templates by language pair.
+func (a *App) SetPromptRules(rules []prompt.Rule) error {
+	return a.cfg.SetPromptRules(rules)
+}
+
+// ─────────────────────────────────────────────────────────────────────────────
+// Glossary
+// ─────────────────────────────────────────────────────────────────────────────
+
+// glossaryFilter limits glossary file dialogs to the supported formats.
+var glossaryFilter = []runtime.FileFilter{{DisplayName: "Glossary (*.csv, *.json)", Pattern: "*.csv;*.json"}}
+
+// GetGlossary returns all glossary entries.
+func (a *App) GetGlossary() ([]glossary.Entry, error) {
+	if a.glossary == nil {
+		return nil, fmt.Errorf("glossary unavailable")
+	}
+	return a.glossary.Entries(), nil
+}
+
+// PutGlossaryEntry adds a glossary entry, or replaces the one for the same
+// term and language pair.
+func (a *App) PutGlossaryEntry(e glossary.Entry) error {
+	if a.glossary == nil {
+		return fmt.Errorf("glossary unavailable")
+	}
+	return a.glossary.Put(e)
+}
+
+// RemoveGlossaryEntry removes the glossary entry for the term and language pair of e.
+func (a *App) RemoveGlossaryEntry(e glossary.Entry) error {
+	if a.glossary == nil {
+		return fmt.Errorf("glossary unavailable")
+	}
+	return a.glossary.Remove(e)
+}
+
+// ImportGlossary merges entries from a CSV or JSON file chosen by the user
+// into the glossary. It returns the number of entries imported, or 0 if the
+// user cancelled.
+func (a *App) ImportGlossary() (int, error) {
+	if a.glossary == nil {
+		return 0, fmt.Errorf("glossary unavailable")
+	}
+
+	path, err := runtime.OpenFileDialog(a.ctx, runtime.OpenDialogOptions{
+		Title:   "Import Glossary",
+		Filters: glossaryFilter,
+	})
+	if err != nil || path == "" {
+		return 0, err
+	}
+
+	format, err := glossary.FormatOf(path)
+	if err != nil {
+		return 0, err
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, fmt.Errorf("open glossary file: %w", err)
+	}
+	defer f.Close()
+
+	return a.glossary.Import(f, format)
+}
+
+// ExportGlossary writes the glossary to a CSV or JSON file chosen by the
+// user. It returns the file path, or "" if the user cancelled.
+func (a *App) ExportGlossary() (string, error) {
+	if a.glossary == nil {
+		return "", fmt.Errorf("glossary unavailable")
+	}
+
+	path, err := runtime.SaveFileDialog(a.ctx, runtime.SaveDialogOptions{
+		Title:           "Export Glossary",
+		DefaultFilename: "glossary.csv",
+		Filters:         glossaryFilter,
+	})
+	if err != nil || path == "" {
+		return "", err
+	}
+
+	format, err := glossary.FormatOf(path)
+	if err != nil {
+		return "", err
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return "", fmt.Errorf("create glossary file: %w", err)
+	}
+	if err := a.glossary.Export(f, format); err != nil {
+		f.Close()
+		return "", fmt.Errorf("export glossary: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return "", fmt.Errorf("close glossary file: %w", err)
+	}
+	return path, nil
+}
+
+// ─────────────────────────────────────────────────────────────────────────────
+// Translation Cache
+// ─────────────────────────────────────────────────────────────────────────────
+
+// GetCacheSettings returns the translation cache TTL and limits.
+func (a *App) GetCacheSettings() config.CacheSettings {
+	return a.cfg.Cache
+}
+
+// SetCacheSettings replaces the translation cache TTL and limits. The TTL
+// applies to translations cached from now on; the limits take effect when
+// the app is restarted.
+func (a *App) SetCacheSettings(cs config.CacheSettings) error {
+	return a.cfg.SetCacheSettings(cs)
+}
+
+// GetCacheStats returns the cache hit rate and the tokens saved by hits,
+// persisted across restarts, in total and by provider, model and language
+// pair.
+func (a *App) GetCacheStats() (cache.Report, error) {
+	if a.cache == nil {
+		return cache.Report{}, fmt.Errorf("cache unavailable")
+	}
+	return a.cache.Report()
+}
+
+// ResetCacheStats starts the cache statistics afresh.
+func (a *App) ResetCacheStats() error {
+	if a.cache == nil {
+		return fmt.Errorf("cache unavailable")
+	}
+	return a.cache.ResetStats()
+}
+
+// cacheSearchLimit bounds the entries returned to the cache browser.
+const cacheSearchLimit = 200
+
+// SearchCache returns the newest cached translations whose source text or
+// translation contains every word of query; an empty query lists the
+// newest entries.
+func (a *App) SearchCache(query string) ([]cache.Record, error) {
+	if a.cache == nil {
+		return nil, fmt.Errorf("cache unavailable")
+	}
+	return a.cache.Search(query, cacheSearchLimit)
+}
+
+// SearchCachePrefix returns the newest cached translations whose source
+// text starts with prefix.
+func (a *App) SearchCachePrefix(prefix string) ([]cache.Record, error) {
+	if a.cache == nil {
+		return nil, fmt.Errorf("cache unavailable")
+	}
+	return a.cache.SearchPrefix(prefix, cacheSearchLimit)
+}
+
+// DeleteCacheEntry removes a cached translation so that it is translated
+// afresh next time.
+func (a *App) DeleteCacheEntry(key string) error {
+	if a.cache == nil {
+		return fmt.Errorf("cache unavailable")
+	}
+	return a.cache.Delete(key)
+}
+
+// cacheFilter limits cache file dialogs to the supported formats.
+var cacheFilter = []runtime.FileFilter{{DisplayName: "Translation Cache (*.jsonl, *.tmx)", Pattern: "*.jsonl;*.tmx"}}
+
+// ExportCache writes the cached translations matching f to a JSON Lines or
+// TMX file chosen by the user. It returns the file path, or "" if the user
+// cancelled.
+func (a *App) ExportCache(f cache.Filter) (string, error) {
+	if a.cache == nil {
+		return "", fmt.Errorf("cache unavailable")
+	}
+
+	path, err := runtime.SaveFileDialog(a.ctx, runtime.SaveDialogOptions{
+		Title:           "Export Translation Cache",
+		DefaultFilename: "transy-cache.tmx",
+		Filters:         cacheFilter,
+	})
+	if err != nil || path == "" {
+		return "", err
+	}
+
+	format, err := cache.FormatOf(path)
+	if err != nil {
+		return "", err
+	}
+	file, err := os.Create(path)
+	if err != nil {
+		return "", fmt.Errorf("create cache file: %w", err)
+	}
+	if _, err := a.cache.Export(file, format, f); err != nil {
+		file.Close()
+		return "", err
+	}
+	if err := file.Close(); err != nil {
+		return "", fmt.Errorf("close cache file: %w", err)
+	}
+	return path, nil
+}
+
+// ImportCache adds the translations matching f from a JSON Lines or TMX
+// file chosen by the user to the cache, resolving conflicts with cached
+// entries by policy. The result is empty if the user cancelled.
+func (a *App) ImportCache(f cache.Filter, policy cache.ConflictPolicy) (cache.ImportResult, error) {
+	if a.cache == nil {
+		return cache.ImportResult{}, fmt.Errorf("cache unavailable")
+	}
+
+	path, err := runtime.OpenFileDialog(a.ctx, runtime.OpenDialogOptions{
+		Title:   "Import Translation Cache",
+		Filters: cacheFilter,
+	})
+	if err != nil || path == "" {
+		return cache.ImportResult{}, err
+	}
+
+	format, err := cache.FormatOf(path)
+	if err != nil {
+		return cache.ImportResult{}, err
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return cache.ImportResult{}, fmt.Errorf("open cache file: %w", err)
+	}
+	defer file.Close()
+
+	return a.cache.Import(file, format, f, policy, a.cfg.CacheTTL(nil))
+}
+
+// ─────────────────────────────────────────────────────────────────────────────
+// Translation Memory
+// ─────────────────────────────────────────────────────────────────────────────
+
+// GetMemorySettings returns the translation memory settings.
+func (a *App) GetMemorySettings() config.MemorySettings {
+	return a.cfg.Memory
+}
+
+// SetMemorySettings replaces the translation memory settings.
+func (a *App) SetMemorySettings(m config.MemorySettings) error {
+	return a.cfg.SetMemorySettings(m)
+}
+
+// ─────────────────────────────────────────────────────────────────────────────
+// Verification
+// ─────────────────────────────────────────────────────────────────────────────
+
+// GetVerificationSettings returns the providers used to verify translations.
+func (a *App) GetVerificationSettings() config.VerificationSettings {
+	return a.cfg.Verification
+}
+
+// SetVerificationSettings replaces the providers used to verify translations.
+func (a *App) SetVerificationSettings(v config.VerificationSettings) error {
+	return a.cfg.SetVerificationSettings(v)
+}
+
+// ─────────────────────────────────────────────────────────────────────────────
+// Telemetry
+// ─────────────────────────────────────────────────────────────────────────────
+
+// The translation pipeline is traced from TranslateWithLLM through the cache
+// lookup to the LLM call (instrumented in llm), along with language
+// detection and OCR. Spans and instruments are no-ops unless an exporter is
+// configured.
+var (
+	tracer = otel.Tracer("go.aimuz.me/transy")
+	meter  = otel.Meter("go.aimuz.me/transy")
+
+	translationDuration, _ = meter.Float64Histogram("transy.translation.duration",
+		metric.WithUnit("s"),
+		metric.WithDescription("Duration of translations, including cache lookups and failover"))
+	cacheLookups, _ = meter.Int64Counter("transy.cache.lookups",
+		metric.WithDescription("Translation cache lookups"))
+)
+
+// GetTelemetrySettings returns where traces and metrics are exported.
+func (a *App) GetTelemetrySettings() telemetry.Settings {
+	return a.cfg.Telemetry
+}
+
+// SetTelemetrySettings replaces where traces and metrics are exported.
+// The change takes effect when the app is restarted.
+func (a *App) SetTelemetrySettings(t telemetry.Settings) error {
+	return a.cfg.SetTelemetrySettings(t)
+}
+
+// resultAttributes describe the outcome of a translation.
+func resultAttributes(result types.TranslateResult) []attribute.KeyValue {
+	return []attribute.KeyValue{
+		attribute.String("transy.provider", result.Provider),
+		attribute.Bool("transy.cache_hit", result.Usage.CacheHit),
+		attribute.Int("gen_ai.usage.input_tokens", result.Usage.PromptTokens),
+		attribute.Int("gen_ai.usage.output_tokens", result.Usage.CompletionTokens),
+	}
+}
+
+// endSpan records err and the latency since start on span, then ends it.
+func endSpan(span trace.Span, start time.Time, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.SetAttributes(attribute.Int64("transy.latency_ms", time.Since(start).Milliseconds()))
+	span.End()
+}
+
+// ─────────────────────────────────────────────────────────────────────────────
+// Debug Logging
+// ─────────────────────────────────────────────────────────────────────────────
+
+// The debug log rotates at debugLogMaxSize, keeping debugLogBackups old files.
+const (
+	debugLogMaxSize = 5 << 20
+	debugLogBackups = 3
+)
+
+// GetDebugLogging reports whether LLM requests and responses are logged.
+func (a *App) GetDebugLogging() bool {
+	return a.cfg.DebugLog
+}
+
+// SetDebugLogging turns logging of LLM requests and responses on or off.
+func (a *App) SetDebugLogging(enabled bool) error {
+	if enabled {
+		if err := a.openDebugLog(); err != nil {
+			return err
+		}
+	} else {
+		a.closeDebugLog()
+	}
+	return a.cfg.SetDebugLog(enabled)
+}
+
+// GetDebugLogPath returns the path of the debug log.
+func (a *App) GetDebugLogPath() (string, error) {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("get config dir: %w", err)
+	}
+	return filepath.Join(configDir, "transy", "logs", "llm-debug.log"), nil
+}
+
+// openDebugLog starts recording LLM calls to the debug log.
+func (a *App) openDebugLog() error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.debugLog != nil {
+		return nil
+	}
+	path, err := a.GetDebugLogPath()
+	if err != nil {
+		return err
+	}
+	f, err := logfile.Open(path, debugLogMaxSize, debugLogBackups)
+	if err != nil {
+		return fmt.Errorf("open debug log: %w", err)
+	}
+	a.debugLog = f
+	llm.SetDebugLog(f)
+	slog.Info("debug logging enabled", "path", path)
+	return nil
+}
+
+// closeDebugLog stops recording LLM calls.
+func (a *App) closeDebugLog() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.debugLog == nil {
+		return
+	}
+	llm.SetDebugLog(nil)
+	if err := a.debugLog.Close(); err != nil {
+		slog.Error("close debug log", "error", err)
+	}
+	a.debugLog = nil
+}
+
 // ─────────────────────────────────────────────────────────────────────────────
 // Language Settings
 // ─────────────────────────────────────────────────────────────────────────────
@@ -216,7 +832,14 @@ func (a *App) SetDefaultLanguage(src, dst string) error {
 }
 
 func (a *App) DetectLanguage(text string) types.DetectResult {
+	_, span := tracer.Start(a.ctx, "DetectLanguage")
+	start := time.Now()
 	code, name := langdetect.Detect(text)
+	span.SetAttributes(
+		attribute.Int("transy.text_length", utf8.RuneCountInString(text)),
+		attribute.String("transy.detected_lang", code),
+	)
+	endSpan(span, start, nil)
 
 	target := "en"
 	if code != "auto" && a.cfg.DefaultLanguages != nil {
@@ -236,49 +859,604 @@ func (a *App) DetectLanguage(text string) types.DetectResult {
 // Translation
 // ─────────────────────────────────────────────────────────────────────────────
 
-func (a *App) TranslateWithLLM(req types.TranslateRequest) (types.TranslateResult, error) {
-	provider := a.GetActiveProvider()
-	if provider == nil {
+// TranslateWithLLM translates the request with the active provider, failing
+// over to the configured fallback providers on transient or quota errors.
+// The translation is streamed to the frontend through "translate-delta"
+// events tagged with req.ID, followed by a "translate-done" event.
+// Starting a new translation cancels the previous one still in flight.
+// If req.Verify is set, the result of a text translation is then verified;
+// see verifyTranslation.
+func (a *App) TranslateWithLLM(req types.TranslateRequest) (result types.TranslateResult, err error) {
+	if req.ID == "" {
+		req.ID = newRequestID()
+	}
+
+	ctx, done := a.beginTranslation(req.ID)
+	defer done()
+
+	ctx, span := tracer.Start(ctx, "TranslateWithLLM", trace.WithAttributes(
+		attribute.String("transy.request_id", req.ID),
+		attribute.String("transy.source_lang", req.SourceLang),
+		attribute.String("transy.target_lang", req.TargetLang),
+		attribute.Int("transy.text_length", utf8.RuneCountInString(req.Text)),
+		attribute.Bool("transy.image", req.Image != ""),
+	))
+	defer func(start time.Time) {
+		attrs := resultAttributes(result)
+		if err != nil {
+			attrs = append(attrs, attribute.String("error.type", string(llm.CodeOf(err))))
+		}
+		translationDuration.Record(ctx, time.Since(start).Seconds(), metric.WithAttributes(attrs...))
+		span.SetAttributes(attrs...)
+		endSpan(span, start, err)
+	}(time.Now())
+
+	chain := a.cfg.ProviderChain()
+	if len(chain) == 0 {
 		return types.TranslateResult{}, fmt.Errorf("no active provider configured")
 	}
+	if req.Image != "" {
+		// Only providers set up for vision can read the image.
+		chain = slices.DeleteFunc(chain, func(p types.Provider) bool {
+			return p.ScreenshotMode != types.ScreenshotModeVision
+		})
+		if len(chain) == 0 {
+			return types.TranslateResult{}, fmt.Errorf("no vision provider configured")
+		}
+	}
+
+	started := false
+	onDelta := func(delta string) {
+		started = true
+		runtime.EventsEmit(a.ctx, "translate-delta", types.TranslateDelta{ID: req.ID, Delta: delta})
+	}
+
+	for i := range chain {
+		var sess *translationSession
+		result, sess, err = a.translateWith(ctx, &chain[i], req, onDelta)
+		if err == nil {
+			result.SessionID = a.saveSession(sess)
+			a.emitDone(req.ID, result)
+			if req.Verify && req.Image == "" {
+				result.Verification = a.verifyTranslation(ctx, &chain[i], req, result.Text)
+			}
+			return result, nil
+		}
+		// Once output has reached the frontend, switching providers would
+		// splice two different translations together.
+		if started || !llm.Failover(err) {
+			break
+		}
+		if i+1 < len(chain) {
+			slog.Warn("provider failed, trying fallback",
+				"provider", chain[i].Name, "fallback", chain[i+1].Name, "error", err)
+		}
+	}
+	return types.TranslateResult{}, fmt.Errorf("translate %q: %w", truncate(req.Text, 32), err)
+}
+
+// verifyTranslation estimates the quality of text, p's translation of req.
+// The translation is back-translated into the source language and a judge
+// model assesses it against the source, listing MQM-style errors. Both steps
+// use p unless the verification settings name other providers. Failures are
+// reported in the verification rather than failing the translation.
+func (a *App) verifyTranslation(ctx context.Context, p *types.Provider, req types.TranslateRequest, text string) *types.Verification {
+	v := &types.Verification{}
+	settings := a.cfg.Verification
+
+	backTranslator, judge := p, p
+	if settings.BackTranslator != "" {
+		backTranslator = a.cfg.GetProvider(settings.BackTranslator)
+	}
+	if settings.Judge != "" {
+		judge = a.cfg.GetProvider(settings.Judge)
+	}
+	if backTranslator == nil || judge == nil {
+		v.Error = "verification provider not found"
+		return v
+	}
+
+	sourceLang := req.SourceLang
+	if sourceLang == "auto" || sourceLang == "" {
+		if sourceLang, _ = langdetect.Detect(req.Text); sourceLang == "auto" {
+			sourceLang = ""
+		}
+	}
+
+	// Without a known source language there is nothing to translate back into;
+	// the judge still compares the translation with the source.
+	if sourceLang != "" {
+		v.BackTranslator = backTranslator.Name
+		messages, err := buildMessages(backTranslator, promptContext{template: prompt.Default}, types.TranslateRequest{
+			Text:       text,
+			SourceLang: req.TargetLang,
+			TargetLang: sourceLang,
+		})
+		if err != nil {
+			v.Error = err.Error()
+			return v
+		}
+		resp, err := a.callLLM(ctx, backTranslator, messages, nil)
+		if err != nil {
+			v.Error = fmt.Sprintf("back-translate: %v", err)
+			return v
+		}
+		v.BackTranslation = resp.Text
+	}
+
+	v.Judge = judge.Name
+	resp, err := a.callLLM(ctx, judge, []llm.Message{
+		{Role: "system", Content: quality.JudgeSystemPrompt},
+		{Role: "user", Content: quality.JudgePrompt(
+			prompt.LanguageName(sourceLang), prompt.LanguageName(req.TargetLang),
+			req.Text, text, v.BackTranslation,
+		)},
+	}, nil)
+	if err != nil {
+		v.Error = fmt.Sprintf("judge: %v", err)
+		return v
+	}
+	assessment, err := quality.ParseJudgement(resp.Text)
+	if err != nil {
+		v.Error = err.Error()
+		return v
+	}
 
-	cacheKey := a.translationCacheKey(provider, req)
+	v.Score = assessment.Score
+	for _, e := range assessment.Errors {
+		v.Errors = append(v.Errors, types.QualityIssue{
+			Category:    e.Category,
+			Severity:    e.Severity,
+			Span:        e.Span,
+			Explanation: e.Explanation,
+			Suggestion:  e.Suggestion,
+		})
+	}
+	return v
+}
+
+// translateWith translates the request with a single provider, serving it
+// from the cache when possible and caching fresh results under that provider.
+// Glossary terms that the output doesn't apply are flagged in the result,
+// and similar earlier translations from the translation memory are attached.
+// The conversation is returned as an unsaved session for later refinement.
+func (a *App) translateWith(ctx context.Context, p *types.Provider, req types.TranslateRequest, onDelta llm.StreamFunc) (result types.TranslateResult, sess *translationSession, err error) {
+	ctx, span := tracer.Start(ctx, "translate "+p.Name, trace.WithAttributes(
+		attribute.String("transy.provider", p.Name),
+		attribute.String("gen_ai.request.model", p.Model),
+	))
+	defer func(start time.Time) {
+		span.SetAttributes(resultAttributes(result)...)
+		endSpan(span, start, err)
+	}(time.Now())
+
+	pc := a.newPromptContext(p, req)
+	cacheKey := a.translationCacheKey(p, pc, req)
+
+	messages, err := buildMessages(p, pc, req)
+	if err != nil {
+		return types.TranslateResult{}, nil, err
+	}
+	session := func(text string) *translationSession {
+		return &translationSession{
+			provider: p.Name,
+			messages: append(messages, llm.Message{Role: "assistant", Content: text}),
+			terms:    pc.terms,
+		}
+	}
 
 	// Check cache first.
-	if result, ok := a.getCachedTranslation(cacheKey); ok {
-		return result, nil
+	if result, ok := a.getCachedTranslation(ctx, cacheKey, cacheLabels(p, req)); ok {
+		result.Provider = p.Name
+		result.GlossaryIssues = glossaryIssues(result.Text, pc.terms)
+		result.MemoryMatches = memoryMatches(pc.matches)
+		return result, session(result.Text), nil
 	}
 
-	// Call LLM API.
-	text, usage, err := a.callLLM(provider, req)
+	// Stream from LLM API.
+	resp, err := a.callLLM(ctx, p, messages, onDelta)
 	if err != nil {
-		return types.TranslateResult{}, fmt.Errorf("translate %q: %w", truncate(req.Text, 32), err)
+		return types.TranslateResult{}, nil, fmt.Errorf("%s: %w", p.Name, err)
+	}
+
+	result = types.TranslateResult{
+		Text:      resp.Text,
+		Reasoning: resp.Reasoning,
+		Usage:     resp.Usage,
+		Provider:  p.Name,
+	}
+
+	// Store result in cache and translation memory (best effort).
+	a.cacheTranslation(cacheKey, p, req, result)
+	a.rememberTranslation(p, req, result)
+
+	result.GlossaryIssues = glossaryIssues(result.Text, pc.terms)
+	result.MemoryMatches = memoryMatches(pc.matches)
+	return result, session(result.Text), nil
+}
+
+// promptContext is what shapes a translation prompt besides the request.
+type promptContext struct {
+	template prompt.Template
+	terms    []glossary.Entry // glossary entries occurring in the source text
+	matches  []tm.Match       // similar earlier translations, best first
+	examples []tm.Match       // the matches fed to the model as few-shot examples
+}
+
+// memoryMatchLimit is the number of translation memory matches shown with
+// a translation, unless more are used as examples.
+const memoryMatchLimit = 3
+
+// newPromptContext selects the prompt template, glossary terms and
+// translation memory matches for translating req with p.
+func (a *App) newPromptContext(p *types.Provider, req types.TranslateRequest) promptContext {
+	pc := promptContext{template: a.cfg.PromptTemplate(p, req.SourceLang, req.TargetLang)}
+	if req.Image != "" {
+		return pc
+	}
+
+	if a.glossary != nil {
+		pc.terms = a.glossary.Match(req.Text, req.SourceLang, req.TargetLang)
 	}
 
-	// Store result in cache (best effort).
-	a.cacheTranslation(cacheKey, text, usage)
+	if settings := a.cfg.Memory; a.memory != nil && !settings.Disabled {
+		// One more than needed, in case the text's own earlier translation is
+		// among them. That one is left out: as an example it would show the
+		// model its previous output and change the cache key on every repeat.
+		limit := max(memoryMatchLimit, settings.Examples)
+		matches, err := a.memory.Lookup(req.Text, req.SourceLang, req.TargetLang, limit+1, settings.MinScore)
+		if err != nil {
+			slog.Error("look up translation memory", "error", err)
+		}
+		matches = slices.DeleteFunc(matches, func(m tm.Match) bool {
+			return tm.SameSource(m.Source, req.Text)
+		})
+		matches = matches[:min(limit, len(matches))]
+		pc.matches = matches
+		pc.examples = matches[:min(settings.Examples, len(matches))]
+	}
+	return pc
+}
 
-	return types.TranslateResult{Text: text, Usage: usage}, nil
+// cacheParts returns what distinguishes cached translations made with
+// this context. The template's text is included along with its ID so that
+// editing it takes effect.
+func (pc promptContext) cacheParts() []string {
+	t := pc.template
+	parts := []string{t.ID, t.Text, t.Domain, t.Tone}
+	for _, e := range pc.terms {
+		parts = append(parts, e.Source+"="+e.Translation())
+	}
+	for _, m := range pc.examples {
+		parts = append(parts, m.Source+"=>"+m.Target)
+	}
+	return parts
+}
+
+// glossaryTerms returns the matched glossary entries as template variables.
+func (pc promptContext) glossaryTerms() []prompt.Term {
+	terms := make([]prompt.Term, len(pc.terms))
+	for i, e := range pc.terms {
+		terms[i] = prompt.Term{Source: e.Source, Target: e.Translation()}
+	}
+	return terms
+}
+
+// memoryMatches converts translation memory matches for the frontend.
+func memoryMatches(matches []tm.Match) []types.MemoryMatch {
+	var out []types.MemoryMatch
+	for _, m := range matches {
+		out = append(out, types.MemoryMatch{
+			Source:   m.Source,
+			Target:   m.Target,
+			Score:    m.Score,
+			Provider: m.Provider,
+		})
+	}
+	return out
+}
+
+// rememberTranslation records a fresh text translation in the translation
+// memory (best effort).
+func (a *App) rememberTranslation(p *types.Provider, req types.TranslateRequest, result types.TranslateResult) {
+	if a.memory == nil || a.cfg.Memory.Disabled || req.Image != "" || result.Text == "" {
+		return
+	}
+
+	err := a.memory.Add(tm.Segment{
+		Source:     req.Text,
+		Target:     result.Text,
+		SourceLang: req.SourceLang,
+		TargetLang: req.TargetLang,
+		Provider:   p.Name,
+		Model:      p.Model,
+	})
+	if err != nil {
+		slog.Error("add to translation memory", "error", err)
+	}
+}
+
+// glossaryIssues reports the glossary terms not applied in text.
+func glossaryIssues(text string, terms []glossary.Entry) []types.GlossaryIssue {
+	var issues []types.GlossaryIssue
+	for _, e := range glossary.Check(text, terms) {
+		issues = append(issues, types.GlossaryIssue{Source: e.Source, Expected: e.Translation()})
+	}
+	return issues
+}
+
+// TranslateCompare translates the request with each of the named providers
+// concurrently so their output can be compared side by side. Results are
+// returned in the order of providerNames; a provider that fails reports its
+// error in its result rather than failing the whole comparison. Nothing is
+// streamed, and the comparison can be aborted with CancelTranslation(req.ID).
+func (a *App) TranslateCompare(req types.TranslateRequest, providerNames []string) ([]types.CompareResult, error) {
+	if len(providerNames) == 0 {
+		return nil, fmt.Errorf("no providers to compare")
+	}
+
+	providers := make([]*types.Provider, len(providerNames))
+	for i, name := range providerNames {
+		if providers[i] = a.cfg.GetProvider(name); providers[i] == nil {
+			return nil, fmt.Errorf("provider not found: %s", name)
+		}
+	}
+
+	if req.ID == "" {
+		req.ID = newRequestID()
+	}
+	ctx, done := a.trackTranslation(req.ID)
+	defer done()
+
+	results := make([]types.CompareResult, len(providers))
+	var wg sync.WaitGroup
+	for i, p := range providers {
+		wg.Go(func() {
+			start := time.Now()
+			result, _, err := a.translateWith(ctx, p, req, nil)
+
+			results[i] = types.CompareResult{
+				Provider:  p.Name,
+				Model:     p.Model,
+				Text:      result.Text,
+				Reasoning: result.Reasoning,
+				Usage:     result.Usage,
+				LatencyMs: time.Since(start).Milliseconds(),
+			}
+			if err != nil {
+				results[i].Error = translateError(err)
+			}
+		})
+	}
+	wg.Wait()
+
+	return results, nil
+}
+
+// RefineTranslation revises the translation of a session according to a
+// follow-up instruction such as "more formal" or "shorter", continuing the
+// conversation with the provider that made it. The revision is streamed like
+// a translation, with the session ID as its request ID, so it can also be
+// aborted with CancelTranslation(sessionID).
+func (a *App) RefineTranslation(sessionID, instruction string) (types.TranslateResult, error) {
+	instruction = strings.TrimSpace(instruction)
+	if instruction == "" {
+		return types.TranslateResult{}, fmt.Errorf("refinement instruction required")
+	}
+
+	sess, ok := a.getSession(sessionID)
+	if !ok {
+		return types.TranslateResult{}, fmt.Errorf("translation session not found or expired")
+	}
+	p := a.cfg.GetProvider(sess.provider)
+	if p == nil {
+		return types.TranslateResult{}, fmt.Errorf("provider not found: %s", sess.provider)
+	}
+
+	ctx, done := a.beginTranslation(sessionID)
+	defer done()
+
+	messages := append(slices.Clip(sess.messages), llm.Message{
+		Role: "user",
+		Content: fmt.Sprintf("Revise your translation: %s\n\n"+
+			"Reply with the complete revised translation only.", instruction),
+	})
+	resp, err := a.callLLM(ctx, p, messages, func(delta string) {
+		runtime.EventsEmit(a.ctx, "translate-delta", types.TranslateDelta{ID: sessionID, Delta: delta})
+	})
+	if err != nil {
+		return types.TranslateResult{}, fmt.Errorf("refine translation: %s: %w", p.Name, err)
+	}
+
+	sess.messages = append(messages, llm.Message{Role: "assistant", Content: resp.Text})
+	a.updateSession(sessionID, sess)
+
+	result := types.TranslateResult{
+		Text:           resp.Text,
+		Reasoning:      resp.Reasoning,
+		Usage:          resp.Usage,
+		Provider:       p.Name,
+		SessionID:      sessionID,
+		GlossaryIssues: glossaryIssues(resp.Text, sess.terms),
+	}
+	a.emitDone(sessionID, result)
+	return result, nil
+}
+
+// translationSession is the conversation behind a translation, kept so the
+// result can be refined with follow-up instructions.
+type translationSession struct {
+	provider string
+	messages []llm.Message    // including the latest translation as the last message
+	terms    []glossary.Entry // glossary entries to check revisions against
+	expires  time.Time
+}
+
+// sessionTTL is how long a translation session stays refinable after its
+// last use.
+const sessionTTL = 30 * time.Minute
+
+// maxSessions is how many translation sessions are kept at most. Every
+// translation starts one, so typing and screenshots would otherwise pile
+// them up for the whole TTL.
+const maxSessions = 50
+
+// saveSession stores a new session and returns its ID. Expired sessions are
+// pruned at the same time, and the least recently used ones dropped to stay
+// within maxSessions.
+func (a *App) saveSession(sess *translationSession) string {
+	id := newRequestID()
+	now := time.Now()
+	sess.expires = now.Add(sessionTTL)
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	maps.DeleteFunc(a.sessions, func(_ string, s *translationSession) bool {
+		return now.After(s.expires)
+	})
+	for len(a.sessions) >= maxSessions {
+		// Expiry moves with each use, so the earliest is the least recent.
+		oldest := ""
+		for k, s := range a.sessions {
+			if oldest == "" || s.expires.Before(a.sessions[oldest].expires) {
+				oldest = k
+			}
+		}
+		delete(a.sessions, oldest)
+	}
+	a.sessions[id] = sess
+	return id
+}
+
+// getSession returns a copy of the session with the given ID if it hasn't expired.
+func (a *App) getSession(id string) (translationSession, bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	sess, ok := a.sessions[id]
+	if !ok {
+		return translationSession{}, false
+	}
+	if time.Now().After(sess.expires) {
+		delete(a.sessions, id)
+		return translationSession{}, false
+	}
+	return *sess, true
+}
+
+// updateSession replaces a session and extends its expiry.
+func (a *App) updateSession(id string, sess translationSession) {
+	sess.expires = time.Now().Add(sessionTTL)
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.sessions[id] = &sess
+}
+
+// CancelTranslation aborts the in-flight translation with the given request ID.
+// It is a no-op if the translation has already finished.
+func (a *App) CancelTranslation(id string) {
+	a.mu.Lock()
+	cancel, ok := a.inflight[id]
+	a.mu.Unlock()
+
+	if ok {
+		cancel()
+	}
+}
+
+// beginTranslation registers a cancellable translation and cancels the one
+// it replaces. The returned func must be called when the translation ends.
+func (a *App) beginTranslation(id string) (context.Context, func()) {
+	a.mu.Lock()
+	if prev, ok := a.inflight[a.latest]; ok {
+		prev()
+	}
+	a.latest = id
+	a.mu.Unlock()
+
+	return a.trackTranslation(id)
+}
+
+// trackTranslation registers a cancellable translation without replacing
+// the latest one. The returned func must be called when the translation ends.
+func (a *App) trackTranslation(id string) (context.Context, func()) {
+	ctx, cancel := context.WithCancel(a.ctx)
+
+	a.mu.Lock()
+	a.inflight[id] = cancel
+	a.mu.Unlock()
+
+	return ctx, func() {
+		a.mu.Lock()
+		delete(a.inflight, id)
+		a.mu.Unlock()
+		cancel()
+	}
+}
+
+// cancelAllTranslations aborts every in-flight translation.
+func (a *App) cancelAllTranslations() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	for _, cancel := range a.inflight {
+		cancel()
+	}
+}
+
+// emitDone notifies the frontend that the translation with the given ID finished.
+func (a *App) emitDone(id string, result types.TranslateResult) {
+	runtime.EventsEmit(a.ctx, "translate-done", types.TranslateDone{
+		ID:        id,
+		Text:      result.Text,
+		Reasoning: result.Reasoning,
+		Usage:     result.Usage,
+		Provider:  result.Provider,
+	})
 }
 
 // translationCacheKey generates a cache key for the translation request.
-func (a *App) translationCacheKey(p *types.Provider, req types.TranslateRequest) string {
-	return cache.GenerateKey(p.Name, p.Model, req.SourceLang, req.TargetLang, req.Text)
+// Images are keyed by their digest rather than their full contents.
+func (a *App) translationCacheKey(p *types.Provider, pc promptContext, req types.TranslateRequest) string {
+	text := req.Text
+	if req.Image != "" {
+		sum := sha256.Sum256([]byte(req.Image))
+		text = "image:" + hex.EncodeToString(sum[:])
+	}
+	return cache.GenerateKey(p.Name, p.Model, req.SourceLang, req.TargetLang, text, pc.cacheParts()...)
 }
 
-// getCachedTranslation retrieves a cached translation if available.
-func (a *App) getCachedTranslation(key string) (types.TranslateResult, bool) {
+// cacheLabels break down the cache statistics of p's translations of req.
+func cacheLabels(p *types.Provider, req types.TranslateRequest) cache.Labels {
+	return cache.Labels{
+		Provider:   p.Name,
+		Model:      p.Model,
+		SourceLang: req.SourceLang,
+		TargetLang: req.TargetLang,
+	}
+}
+
+// getCachedTranslation retrieves a cached translation if available,
+// recording the lookup in the statistics under labels.
+func (a *App) getCachedTranslation(ctx context.Context, key string, labels cache.Labels) (types.TranslateResult, bool) {
 	if a.cache == nil {
 		return types.TranslateResult{}, false
 	}
 
-	entry, found := a.cache.Get(key)
+	ctx, span := tracer.Start(ctx, "cache.get")
+	entry, found := a.cache.Lookup(key, labels)
+	span.SetAttributes(attribute.Bool("transy.cache_hit", found))
+	span.End()
+	cacheLookups.Add(ctx, 1, metric.WithAttributes(attribute.Bool("transy.cache_hit", found)))
 	if !found {
 		return types.TranslateResult{}, false
 	}
 
 	return types.TranslateResult{
-		Text: entry.Text,
+		Text:      entry.Text,
+		Reasoning: entry.Reasoning,
 		Usage: types.Usage{
 			PromptTokens:     entry.Usage.PromptTokens,
 			CompletionTokens: entry.Usage.CompletionTokens,
@@ -288,40 +1466,139 @@ func (a *App) getCachedTranslation(key string) (types.TranslateResult, bool) {
 	}, true
 }
 
-// cacheTranslation stores a translation result in the cache.
-func (a *App) cacheTranslation(key, text string, usage types.Usage) {
+// cacheTranslation stores p's translation of req in the cache. Images are
+// recorded without source text.
+func (a *App) cacheTranslation(key string, p *types.Provider, req types.TranslateRequest, result types.TranslateResult) {
 	if a.cache == nil {
 		return
 	}
 
 	entry := &cache.Entry{
-		Text: text,
+		Text:      result.Text,
+		Reasoning: result.Reasoning,
 		Usage: cache.Usage{
-			PromptTokens:     usage.PromptTokens,
-			CompletionTokens: usage.CompletionTokens,
-			TotalTokens:      usage.TotalTokens,
+			PromptTokens:     result.Usage.PromptTokens,
+			CompletionTokens: result.Usage.CompletionTokens,
+			TotalTokens:      result.Usage.TotalTokens,
 		},
-		CreatedAt: time.Now(),
+		CreatedAt:  time.Now(),
+		SourceLang: req.SourceLang,
+		TargetLang: req.TargetLang,
+		Provider:   p.Name,
+		Model:      p.Model,
+	}
+	if req.Image == "" {
+		entry.SourceText = req.Text
 	}
 
-	if err := a.cache.Set(key, entry, cache.DefaultTTL); err != nil {
+	if err := a.cache.Set(key, entry, a.cfg.CacheTTL(p)); err != nil {
 		slog.Warn("cache translation", "error", err)
 	}
 }
 
-// callLLM invokes the LLM API to perform translation.
-func (a *App) callLLM(p *types.Provider, req types.TranslateRequest) (string, types.Usage, error) {
-	client := llm.NewClient(p)
+// buildMessages builds the conversation asking p to translate req. The
+// instruction for text is rendered from the context's template with its
+// glossary terms, preceded by any translation memory examples.
+func buildMessages(p *types.Provider, pc promptContext, req types.TranslateRequest) ([]llm.Message, error) {
+	sourceLang := prompt.LanguageName(req.SourceLang)
+	targetLang := prompt.LanguageName(req.TargetLang)
 
-	messages := []llm.Message{
-		{Role: "system", Content: p.SystemPrompt},
-		{Role: "user", Content: fmt.Sprintf(
-			"please translate the following text from %s to %s:\n\n%s",
-			req.SourceLang, req.TargetLang, req.Text,
-		)},
+	var user llm.Message
+	if req.Image != "" {
+		data, err := base64.StdEncoding.DecodeString(req.Image)
+		if err != nil {
+			return nil, fmt.Errorf("decode image: %w", err)
+		}
+		from := ""
+		if sourceLang != "" {
+			from = " from " + sourceLang
+		}
+		user = llm.Message{
+			Role: "user",
+			Content: fmt.Sprintf(
+				"please translate all text in this image%s to %s. "+
+					"Keep the reading order and line breaks of the original, "+
+					"and reply with the translation only.",
+				from, targetLang,
+			),
+			Images: []llm.Image{{MIMEType: "image/png", Data: data}},
+		}
+	} else {
+		content, err := pc.template.Render(prompt.Data{
+			SourceLang: sourceLang,
+			TargetLang: targetLang,
+			Text:       req.Text,
+			Glossary:   pc.glossaryTerms(),
+		})
+		if err != nil {
+			return nil, err
+		}
+		user = llm.Message{Role: "user", Content: content}
 	}
 
-	return client.Complete(messages)
+	messages := []llm.Message{{Role: "system", Content: p.SystemPrompt}}
+
+	// Earlier translations of similar text become few-shot turns, the best
+	// match nearest the request.
+	for _, ex := range slices.Backward(pc.examples) {
+		content, err := pc.template.Render(prompt.Data{
+			SourceLang: sourceLang,
+			TargetLang: targetLang,
+			Text:       ex.Source,
+		})
+		if err != nil {
+			return nil, err
+		}
+		messages = append(messages,
+			llm.Message{Role: "user", Content: content},
+			llm.Message{Role: "assistant", Content: ex.Target},
+		)
+	}
+	return append(messages, user), nil
+}
+
+// callLLM sends the conversation to p, streaming deltas to fn.
+// If fn is nil the response is requested in one piece. Clients are pooled
+// per provider so that connections are reused.
+func (a *App) callLLM(ctx context.Context, p *types.Provider, messages []llm.Message, fn llm.StreamFunc) (llm.Response, error) {
+	client, err := a.clients.Get(p)
+	if err != nil {
+		return llm.Response{}, err
+	}
+
+	if fn == nil {
+		return client.Complete(ctx, messages)
+	}
+	return client.Stream(ctx, messages, fn)
+}
+
+// newRequestID returns a random identifier for a translation request.
+func newRequestID() string {
+	b := make([]byte, 8)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// formatError converts errors returned by bound methods for the frontend.
+// LLM failures become a types.TranslateError carrying a machine-readable
+// code; everything else is passed through as its message.
+func formatError(err error) any {
+	var apiErr *llm.Error
+	if !errors.As(err, &apiErr) && llm.CodeOf(err) != llm.CodeCanceled {
+		return err.Error()
+	}
+	return *translateError(err)
+}
+
+// translateError converts err into the structured form used by the frontend.
+func translateError(err error) *types.TranslateError {
+	e := &types.TranslateError{Code: string(llm.CodeOf(err)), Message: err.Error()}
+
+	var apiErr *llm.Error
+	if errors.As(err, &apiErr) {
+		e.RetryAfterMs = apiErr.RetryAfter.Milliseconds()
+	}
+	return e
 }
 
 // truncate shortens a string for logging purposes.
@@ -346,9 +1623,10 @@ func main() {
 		AssetServer: &assetserver.Options{
 			Assets: assets,
 		},
-		OnStartup:  app.startup,
-		OnShutdown: app.shutdown,
-		Bind:       []any{app},
+		OnStartup:      app.startup,
+		OnShutdown:     app.shutdown,
+		Bind:           []any{app},
+		ErrorFormatter: formatError,
 		Mac: &mac.Options{
 			TitleBar: mac.TitleBarHidden(),
 			About: &mac.AboutInfo{
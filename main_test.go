@@ -0,0 +1,95 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+
+	"go.aimuz.me/transy/cache"
+	"go.aimuz.me/transy/config"
+	"go.aimuz.me/transy/internal/types"
+	"go.aimuz.me/transy/tm"
+)
+
+func TestTranslateRepeatHitsCache(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		_, _ = w.Write([]byte(`{"choices":[{"message":{"content":"你好，世界"}}]}`))
+	}))
+	defer srv.Close()
+
+	dir := t.TempDir()
+	c, err := cache.New(filepath.Join(dir, "cache"))
+	if err != nil {
+		t.Fatalf("new cache: %v", err)
+	}
+	defer c.Close()
+	memory, err := tm.Open(filepath.Join(dir, "memory"))
+	if err != nil {
+		t.Fatalf("open memory: %v", err)
+	}
+	defer memory.Close()
+
+	a := NewApp()
+	a.cfg = &config.Config{Memory: config.MemorySettings{Examples: 3}}
+	a.cache = c
+	a.memory = memory
+	defer a.clients.Close()
+
+	p := &types.Provider{Name: "test", Type: "openai-compatible", BaseURL: srv.URL, Model: "m"}
+	req := types.TranslateRequest{Text: "Hello, world", SourceLang: "en", TargetLang: "zh"}
+
+	for i := range 2 {
+		result, _, err := a.translateWith(context.Background(), p, req, nil)
+		if err != nil {
+			t.Fatalf("translate %d: %v", i, err)
+		}
+		if result.Text != "你好，世界" {
+			t.Errorf("translate %d = %q", i, result.Text)
+		}
+		if len(result.MemoryMatches) != 0 {
+			t.Errorf("translate %d: memory matches = %v, want none", i, result.MemoryMatches)
+		}
+	}
+	if n := calls.Load(); n != 1 {
+		t.Errorf("model called %d times, want 1", n)
+	}
+	if s := c.Stats(); s.Hits != 1 {
+		t.Errorf("cache hits = %d, want 1", s.Hits)
+	}
+}
+
+func TestSaveSessionBounded(t *testing.T) {
+	a := NewApp()
+	defer a.clients.Close()
+
+	ids := make([]string, maxSessions+1)
+	for i := range ids {
+		ids[i] = a.saveSession(&translationSession{provider: "test"})
+		if i == 1 {
+			// Refining the first session keeps it; the second is now the
+			// least recently used.
+			sess, ok := a.getSession(ids[0])
+			if !ok {
+				t.Fatal("first session not found")
+			}
+			a.updateSession(ids[0], sess)
+		}
+	}
+
+	if n := len(a.sessions); n != maxSessions {
+		t.Errorf("sessions = %d, want %d", n, maxSessions)
+	}
+	if _, ok := a.getSession(ids[1]); ok {
+		t.Error("least recently used session kept")
+	}
+	for _, i := range []int{0, 2, maxSessions} {
+		if _, ok := a.getSession(ids[i]); !ok {
+			t.Errorf("session %d dropped", i)
+		}
+	}
+}
@@ -0,0 +1,134 @@
+// Package prompt renders the translation instruction sent to LLMs from
+// named text/template templates.
+package prompt
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+)
+
+// DefaultID is the ID of the built-in template used when no other applies.
+// A configured template with this ID replaces it.
+const DefaultID = "default"
+
+// Template is a named translation instruction written with text/template.
+// It is executed with Data as its dot.
+type Template struct {
+	ID     string `json:"id"`
+	Name   string `json:"name"`
+	Text   string `json:"text"`
+	Domain string `json:"domain,omitempty"` // e.g. "medical", exposed as .Domain
+	Tone   string `json:"tone,omitempty"`   // e.g. "formal", exposed as .Tone
+}
+
+// Rule selects a template for a language pair.
+// An empty language matches any language.
+type Rule struct {
+	SourceLang string `json:"source_lang,omitempty"`
+	TargetLang string `json:"target_lang,omitempty"`
+	Template   string `json:"template"`
+}
+
+// Matches reports whether the rule applies to the given language pair.
+func (r Rule) Matches(sourceLang, targetLang string) bool {
+	return (r.SourceLang == "" || r.SourceLang == sourceLang) &&
+		(r.TargetLang == "" || r.TargetLang == targetLang)
+}
+
+// Term is a glossary entry the translation must respect.
+type Term struct {
+	Source string `json:"source"`
+	Target string `json:"target"`
+}
+
+// Data holds the variables available to templates.
+type Data struct {
+	SourceLang string // English name of the source language; empty when auto-detected
+	TargetLang string // English name of the target language
+	Text       string
+	Domain     string
+	Tone       string
+	Glossary   []Term
+}
+
+// Default is the built-in template.
+var Default = Template{
+	ID:   DefaultID,
+	Name: "Default",
+	Text: `Please translate the following text{{if .SourceLang}} from {{.SourceLang}}{{end}} to {{.TargetLang}}.
+{{- if .Domain}}
+The text belongs to the {{.Domain}} domain; use its established terminology.
+{{- end}}
+{{- if .Tone}}
+Use a {{.Tone}} tone.
+{{- end}}
+{{- if .Glossary}}
+Translate these terms as given:
+{{- range .Glossary}}
+- {{.Source}} → {{.Target}}
+{{- end}}
+{{- end}}
+
+{{.Text}}`,
+}
+
+// Validate checks that the template has an ID and that its text parses and
+// refers only to known variables.
+func (t Template) Validate() error {
+	if t.ID == "" {
+		return fmt.Errorf("template id required")
+	}
+	_, err := t.Render(Data{SourceLang: "English", TargetLang: "Chinese", Glossary: []Term{{}}})
+	return err
+}
+
+// Render executes the template with data. The template's Domain and Tone
+// fill in the corresponding variables when data leaves them empty.
+func (t Template) Render(data Data) (string, error) {
+	tmpl, err := template.New(t.ID).Parse(t.Text)
+	if err != nil {
+		return "", fmt.Errorf("parse template %s: %w", t.ID, err)
+	}
+
+	if data.Domain == "" {
+		data.Domain = t.Domain
+	}
+	if data.Tone == "" {
+		data.Tone = t.Tone
+	}
+
+	var b strings.Builder
+	if err := tmpl.Execute(&b, data); err != nil {
+		return "", fmt.Errorf("execute template %s: %w", t.ID, err)
+	}
+	return b.String(), nil
+}
+
+// languageNames maps language codes to the English names given to models,
+// which understand them more reliably than bare codes.
+var languageNames = map[string]string{
+	"zh": "Chinese",
+	"en": "English",
+	"ja": "Japanese",
+	"ko": "Korean",
+	"fr": "French",
+	"de": "German",
+	"es": "Spanish",
+	"ru": "Russian",
+	"it": "Italian",
+	"pt": "Portuguese",
+	"ar": "Arabic",
+}
+
+// LanguageName returns the English name of the language code.
+// It returns "" for "auto" and the code itself for unknown languages.
+func LanguageName(code string) string {
+	if code == "auto" || code == "" {
+		return ""
+	}
+	if name, ok := languageNames[code]; ok {
+		return name
+	}
+	return code
+}
@@ -0,0 +1,123 @@
+package prompt
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRenderDefault(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    Data
+		want    []string
+		notWant []string
+	}{
+		{
+			name:    "plain",
+			data:    Data{SourceLang: "English", TargetLang: "Chinese", Text: "Hello"},
+			want:    []string{"from English to Chinese.", "\n\nHello"},
+			notWant: []string{"domain", "tone", "terms"},
+		},
+		{
+			name:    "auto-detected source",
+			data:    Data{TargetLang: "English", Text: "你好"},
+			want:    []string{"following text to English."},
+			notWant: []string{"from"},
+		},
+		{
+			name: "domain, tone and glossary",
+			data: Data{
+				SourceLang: "English", TargetLang: "Chinese", Text: "Deploy the pod",
+				Domain: "Kubernetes", Tone: "formal",
+				Glossary: []Term{{Source: "pod", Target: "Pod"}},
+			},
+			want: []string{"the Kubernetes domain", "a formal tone", "- pod → Pod"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Default.Render(tt.data)
+			if err != nil {
+				t.Fatalf("render: %v", err)
+			}
+			for _, w := range tt.want {
+				if !strings.Contains(got, w) {
+					t.Errorf("missing %q in:\n%s", w, got)
+				}
+			}
+			for _, w := range tt.notWant {
+				if strings.Contains(got, w) {
+					t.Errorf("unexpected %q in:\n%s", w, got)
+				}
+			}
+		})
+	}
+}
+
+func TestTemplateDefaults(t *testing.T) {
+	tmpl := Template{ID: "t", Text: "{{.Domain}}/{{.Tone}}", Domain: "legal", Tone: "formal"}
+
+	got, err := tmpl.Render(Data{})
+	if err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	if got != "legal/formal" {
+		t.Errorf("got %q, want template defaults", got)
+	}
+
+	got, _ = tmpl.Render(Data{Tone: "casual"})
+	if got != "legal/casual" {
+		t.Errorf("got %q, want data to override template tone", got)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		tmpl    Template
+		wantErr bool
+	}{
+		{"default", Default, false},
+		{"missing id", Template{Text: "{{.Text}}"}, true},
+		{"syntax error", Template{ID: "t", Text: "{{.Text"}, true},
+		{"unknown variable", Template{ID: "t", Text: "{{.Style}}"}, true},
+		{"unknown glossary field", Template{ID: "t", Text: "{{range .Glossary}}{{.Note}}{{end}}"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.tmpl.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRuleMatches(t *testing.T) {
+	tests := []struct {
+		rule     Rule
+		src, tgt string
+		want     bool
+	}{
+		{Rule{SourceLang: "zh", TargetLang: "en"}, "zh", "en", true},
+		{Rule{SourceLang: "zh", TargetLang: "en"}, "en", "zh", false},
+		{Rule{TargetLang: "ja"}, "en", "ja", true},
+		{Rule{SourceLang: "de"}, "fr", "en", false},
+		{Rule{}, "fr", "en", true},
+	}
+
+	for _, tt := range tests {
+		if got := tt.rule.Matches(tt.src, tt.tgt); got != tt.want {
+			t.Errorf("%+v.Matches(%q, %q) = %v, want %v", tt.rule, tt.src, tt.tgt, got, tt.want)
+		}
+	}
+}
+
+func TestLanguageName(t *testing.T) {
+	for code, want := range map[string]string{"zh": "Chinese", "auto": "", "sv": "sv"} {
+		if got := LanguageName(code); got != want {
+			t.Errorf("LanguageName(%q) = %q, want %q", code, got, want)
+		}
+	}
+}
@@ -0,0 +1,110 @@
+// Package quality estimates translation quality with an LLM judge, using
+// MQM (Multidimensional Quality Metrics) error categories and severities.
+package quality
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Error severities and their MQM penalty weights.
+const (
+	SeverityMinor    = "minor"
+	SeverityMajor    = "major"
+	SeverityCritical = "critical"
+)
+
+var severityWeights = map[string]float64{
+	SeverityMinor:    1,
+	SeverityMajor:    5,
+	SeverityCritical: 10,
+}
+
+// Error is a translation error flagged by the judge.
+type Error struct {
+	Category    string `json:"category"` // MQM dimension and subtype, e.g. "accuracy/mistranslation"
+	Severity    string `json:"severity"`
+	Span        string `json:"span"` // the offending text, quoted from the translation
+	Explanation string `json:"explanation,omitempty"`
+	Suggestion  string `json:"suggestion,omitempty"`
+}
+
+// Assessment is the judge's verdict on a translation.
+type Assessment struct {
+	Score  float64 // 0-100; 100 means no errors were found
+	Errors []Error
+}
+
+// JudgeSystemPrompt instructs the judge model.
+const JudgeSystemPrompt = `You are an expert translation quality evaluator using the MQM framework.
+Identify every error in the translation. Categories are "accuracy" (mistranslation, omission, addition, untranslated), "fluency" (grammar, spelling, punctuation, inconsistency), "terminology", "style" and "locale" (formats, conventions); write them as "dimension/subtype".
+Severity is "minor" (noticeable but meaning is clear), "major" (meaning is changed or unclear) or "critical" (misleading, offensive or unusable).
+Reply with a JSON object only, no prose, in the form:
+{"errors": [{"category": "accuracy/mistranslation", "severity": "major", "span": "exact text from the translation", "explanation": "...", "suggestion": "corrected text"}]}
+Reply with {"errors": []} if the translation is correct.`
+
+// JudgePrompt asks the judge to assess a translation. The back-translation
+// into the source language helps the judge spot meaning drift.
+func JudgePrompt(sourceLang, targetLang, source, translation, backTranslation string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Source (%s):\n%s\n\n", orUnknown(sourceLang), source)
+	fmt.Fprintf(&b, "Translation (%s):\n%s\n", orUnknown(targetLang), translation)
+	if backTranslation != "" {
+		fmt.Fprintf(&b, "\nBack-translation of the translation into the source language:\n%s\n", backTranslation)
+	}
+	return b.String()
+}
+
+// ParseJudgement parses the judge's reply and scores it.
+// Unknown severities are treated as major.
+func ParseJudgement(reply string) (Assessment, error) {
+	body := extractJSON(reply)
+	if body == "" {
+		return Assessment{}, fmt.Errorf("judge reply contains no JSON object")
+	}
+
+	var parsed struct {
+		Errors []Error `json:"errors"`
+	}
+	if err := json.Unmarshal([]byte(body), &parsed); err != nil {
+		return Assessment{}, fmt.Errorf("unmarshal judge reply: %w", err)
+	}
+
+	for i, e := range parsed.Errors {
+		e.Severity = strings.ToLower(strings.TrimSpace(e.Severity))
+		if _, ok := severityWeights[e.Severity]; !ok {
+			e.Severity = SeverityMajor
+		}
+		parsed.Errors[i] = e
+	}
+	return Assessment{Score: Score(parsed.Errors), Errors: parsed.Errors}, nil
+}
+
+// Score returns 100 minus the MQM penalty of the errors (minor 1, major 5,
+// critical 10), floored at 0.
+func Score(errors []Error) float64 {
+	penalty := 0.0
+	for _, e := range errors {
+		penalty += severityWeights[e.Severity]
+	}
+	return max(0, 100-penalty)
+}
+
+// extractJSON returns the outermost JSON object in s, which models often
+// wrap in prose or Markdown code fences.
+func extractJSON(s string) string {
+	start := strings.Index(s, "{")
+	end := strings.LastIndex(s, "}")
+	if start == -1 || end < start {
+		return ""
+	}
+	return s[start : end+1]
+}
+
+func orUnknown(lang string) string {
+	if lang == "" {
+		return "detected automatically"
+	}
+	return lang
+}
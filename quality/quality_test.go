@@ -0,0 +1,92 @@
+package quality
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseJudgement(t *testing.T) {
+	tests := []struct {
+		name       string
+		reply      string
+		wantScore  float64
+		wantErrors int
+		wantErr    bool
+	}{
+		{
+			name:      "no errors",
+			reply:     `{"errors": []}`,
+			wantScore: 100,
+		},
+		{
+			name: "fenced with prose",
+			reply: "Here is my assessment:\n```json\n" +
+				`{"errors": [{"category": "accuracy/mistranslation", "severity": "major", "span": "保存"},` +
+				` {"category": "fluency/punctuation", "severity": "Minor", "span": "。"}]}` +
+				"\n```",
+			wantScore:  94,
+			wantErrors: 2,
+		},
+		{
+			name:       "unknown severity counts as major",
+			reply:      `{"errors": [{"category": "style", "severity": "severe", "span": "x"}]}`,
+			wantScore:  95,
+			wantErrors: 1,
+		},
+		{
+			name:    "no json",
+			reply:   "The translation looks good.",
+			wantErr: true,
+		},
+		{
+			name:    "malformed json",
+			reply:   `{"errors": [}`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseJudgement(tt.reply)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseJudgement() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if got.Score != tt.wantScore {
+				t.Errorf("score = %v, want %v", got.Score, tt.wantScore)
+			}
+			if len(got.Errors) != tt.wantErrors {
+				t.Errorf("got %d errors, want %d", len(got.Errors), tt.wantErrors)
+			}
+			for _, e := range got.Errors {
+				if _, ok := severityWeights[e.Severity]; !ok {
+					t.Errorf("severity %q not normalized", e.Severity)
+				}
+			}
+		})
+	}
+}
+
+func TestScoreFloor(t *testing.T) {
+	errs := make([]Error, 12)
+	for i := range errs {
+		errs[i].Severity = SeverityCritical
+	}
+	if got := Score(errs); got != 0 {
+		t.Errorf("Score() = %v, want 0", got)
+	}
+}
+
+func TestJudgePrompt(t *testing.T) {
+	got := JudgePrompt("English", "Chinese", "Save", "保存", "Save")
+	for _, want := range []string{"Source (English):\nSave", "Translation (Chinese):\n保存", "Back-translation"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("prompt missing %q:\n%s", want, got)
+		}
+	}
+	if strings.Contains(JudgePrompt("", "Chinese", "Save", "保存", ""), "Back-translation") {
+		t.Error("prompt mentions a missing back-translation")
+	}
+}
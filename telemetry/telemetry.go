@@ -0,0 +1,154 @@
+// Package telemetry exports OpenTelemetry traces and metrics of the
+// translation pipeline, to an OTLP/HTTP collector or a local JSON file.
+//
+// Instrumented code uses the global otel providers, which are no-ops until
+// Setup installs exporting ones.
+package telemetry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetrichttp"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdoutmetric"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// ServiceName identifies the application in exported telemetry.
+const ServiceName = "transy"
+
+// Exporters.
+const (
+	ExporterNone = ""     // telemetry is disabled
+	ExporterOTLP = "otlp" // OTLP over HTTP to Settings.Endpoint
+	ExporterFile = "file" // JSON lines appended to Settings.File
+)
+
+// Settings configures where telemetry is exported.
+type Settings struct {
+	Exporter string            `json:"exporter,omitempty"`
+	Endpoint string            `json:"endpoint,omitempty"` // OTLP base URL, e.g. "http://localhost:4318"; /v1/traces and /v1/metrics are appended
+	Headers  map[string]string `json:"headers,omitempty"`  // sent with OTLP requests, e.g. for authentication
+	File     string            `json:"file,omitempty"`     // path of the JSON file; empty uses the default path
+}
+
+// Validate checks that the settings can be applied.
+func (s Settings) Validate() error {
+	switch s.Exporter {
+	case ExporterNone, ExporterFile:
+	case ExporterOTLP:
+		u, err := url.Parse(s.Endpoint)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("invalid otlp endpoint %q: want e.g. http://localhost:4318", s.Endpoint)
+		}
+	default:
+		return fmt.Errorf("unknown telemetry exporter %q", s.Exporter)
+	}
+	return nil
+}
+
+// Setup installs global tracer and meter providers exporting as configured.
+// defaultFile is used by the file exporter when s.File is empty. The returned
+// func flushes and stops the exporters; it must be called on exit. Setup does
+// nothing if telemetry is disabled, and is meant to be called once: metric
+// instruments keep the first providers installed.
+func Setup(ctx context.Context, s Settings, defaultFile string) (shutdown func(context.Context) error, err error) {
+	if err := s.Validate(); err != nil {
+		return nil, err
+	}
+
+	var (
+		spans   sdktrace.SpanExporter
+		metrics sdkmetric.Exporter
+		closer  io.Closer
+	)
+	switch s.Exporter {
+	case ExporterNone:
+		return func(context.Context) error { return nil }, nil
+
+	case ExporterOTLP:
+		base := strings.TrimRight(s.Endpoint, "/")
+		spans, err = otlptracehttp.New(ctx,
+			otlptracehttp.WithEndpointURL(base+"/v1/traces"),
+			otlptracehttp.WithHeaders(s.Headers),
+		)
+		if err != nil {
+			return nil, fmt.Errorf("create otlp trace exporter: %w", err)
+		}
+		metrics, err = otlpmetrichttp.New(ctx,
+			otlpmetrichttp.WithEndpointURL(base+"/v1/metrics"),
+			otlpmetrichttp.WithHeaders(s.Headers),
+		)
+		if err != nil {
+			return nil, fmt.Errorf("create otlp metric exporter: %w", err)
+		}
+
+	case ExporterFile:
+		path := s.File
+		if path == "" {
+			path = defaultFile
+		}
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			return nil, fmt.Errorf("create telemetry dir: %w", err)
+		}
+		f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+		if err != nil {
+			return nil, fmt.Errorf("open telemetry file: %w", err)
+		}
+		// Spans and metrics are written by different goroutines.
+		w := &lockedWriter{w: f}
+		if spans, err = stdouttrace.New(stdouttrace.WithWriter(w)); err != nil {
+			f.Close()
+			return nil, fmt.Errorf("create file trace exporter: %w", err)
+		}
+		if metrics, err = stdoutmetric.New(stdoutmetric.WithWriter(w)); err != nil {
+			f.Close()
+			return nil, fmt.Errorf("create file metric exporter: %w", err)
+		}
+		closer = f
+	}
+
+	res, err := resource.Merge(resource.Default(),
+		resource.NewSchemaless(attribute.String("service.name", ServiceName)))
+	if err != nil {
+		return nil, fmt.Errorf("build resource: %w", err)
+	}
+
+	tp := sdktrace.NewTracerProvider(sdktrace.WithBatcher(spans), sdktrace.WithResource(res))
+	mp := sdkmetric.NewMeterProvider(sdkmetric.WithReader(sdkmetric.NewPeriodicReader(metrics)), sdkmetric.WithResource(res))
+	otel.SetTracerProvider(tp)
+	otel.SetMeterProvider(mp)
+
+	return func(ctx context.Context) error {
+		err := errors.Join(tp.Shutdown(ctx), mp.Shutdown(ctx))
+		if closer != nil {
+			err = errors.Join(err, closer.Close())
+		}
+		return err
+	}, nil
+}
+
+// lockedWriter serializes writes so that records don't interleave.
+type lockedWriter struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func (l *lockedWriter) Write(p []byte) (int, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.w.Write(p)
+}
@@ -0,0 +1,74 @@
+package telemetry
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+)
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		s       Settings
+		wantErr bool
+	}{
+		{"disabled", Settings{}, false},
+		{"file", Settings{Exporter: ExporterFile}, false},
+		{"otlp", Settings{Exporter: ExporterOTLP, Endpoint: "http://localhost:4318"}, false},
+		{"otlp without endpoint", Settings{Exporter: ExporterOTLP}, true},
+		{"otlp without scheme", Settings{Exporter: ExporterOTLP, Endpoint: "localhost:4318"}, true},
+		{"unknown exporter", Settings{Exporter: "zipkin"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.s.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestFileExporter(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "telemetry", "spans.jsonl")
+	shutdown, err := Setup(context.Background(), Settings{Exporter: ExporterFile}, path)
+	if err != nil {
+		t.Fatalf("setup: %v", err)
+	}
+
+	_, span := otel.Tracer("test").Start(context.Background(), "translate")
+	span.End()
+	counter, _ := otel.Meter("test").Int64Counter("translations")
+	counter.Add(context.Background(), 1)
+
+	if err := shutdown(context.Background()); err != nil {
+		t.Fatalf("shutdown: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	var sawSpan, sawMetric bool
+	for line := range strings.SplitSeq(strings.TrimSpace(string(data)), "\n") {
+		var record map[string]any
+		if err := json.Unmarshal([]byte(line), &record); err != nil {
+			t.Fatalf("line is not JSON: %q", line)
+		}
+		if record["Name"] == "translate" {
+			sawSpan = true
+		}
+		if _, ok := record["ScopeMetrics"]; ok {
+			sawMetric = true
+		}
+	}
+	if !sawSpan || !sawMetric {
+		t.Errorf("span exported: %v, metrics exported: %v\n%s", sawSpan, sawMetric, data)
+	}
+}
@@ -0,0 +1,90 @@
+package tm
+
+import "unicode"
+
+// similarity returns 1 - d/n, where d is the edit distance between a and b
+// and n the length of the longer one. It may return 0 instead when the
+// score is certainly below minScore: cheap length and trigram filters rule
+// out most candidates before the edit distance is computed, and neither
+// rejects a true match.
+func similarity(a, b []rune, minScore float64) float64 {
+	if string(a) == string(b) {
+		return 1
+	}
+	n := max(len(a), len(b))
+	if n == 0 || n > maxSegmentRunes {
+		return 0
+	}
+
+	// Most edits allowed for the score to reach minScore, with a little
+	// slack so that float error can't exclude an exact boundary.
+	maxDist := int((1-minScore)*float64(n) + 1e-9)
+
+	// Every edit changes the length by at most one.
+	if abs(len(a)-len(b)) > maxDist {
+		return 0
+	}
+
+	// Every edit destroys at most three trigrams, so strings within maxDist
+	// edits share at least n-2-3*maxDist of them (the q-gram count filter).
+	if need := n - 2 - 3*maxDist; need > 0 && commonTrigrams(a, b) < need {
+		return 0
+	}
+
+	return 1 - float64(levenshtein(a, b))/float64(n)
+}
+
+// levenshtein returns the edit distance between a and b, comparing letters
+// case-insensitively.
+func levenshtein(a, b []rune) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if unicode.ToLower(a[i-1]) == unicode.ToLower(b[j-1]) {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(b)]
+}
+
+type trigram [3]rune
+
+// commonTrigrams returns the size of the multiset intersection of the
+// case-folded trigrams of a and b.
+func commonTrigrams(a, b []rune) int {
+	counts := make(map[trigram]int)
+	for i := 0; i+3 <= len(a); i++ {
+		counts[foldTrigram(a[i:i+3])]++
+	}
+
+	common := 0
+	for i := 0; i+3 <= len(b); i++ {
+		g := foldTrigram(b[i : i+3])
+		if counts[g] > 0 {
+			counts[g]--
+			common++
+		}
+	}
+	return common
+}
+
+func foldTrigram(r []rune) trigram {
+	return trigram{unicode.ToLower(r[0]), unicode.ToLower(r[1]), unicode.ToLower(r[2])}
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
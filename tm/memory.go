@@ -0,0 +1,155 @@
+// Package tm provides a translation memory: source/target segment pairs
+// stored in BadgerDB and retrieved by fuzzy matching.
+package tm
+
+import (
+	"cmp"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/dgraph-io/badger/v4"
+	"golang.org/x/text/unicode/norm"
+)
+
+// DefaultMinScore is the similarity below which segments aren't considered
+// matches.
+const DefaultMinScore = 0.7
+
+// maxSegmentRunes bounds the length of segments compared by edit distance.
+// Longer segments are stored but only ever match exactly.
+const maxSegmentRunes = 2000
+
+// Segment is a translated source text.
+type Segment struct {
+	Source     string    `json:"source"`
+	Target     string    `json:"target"`
+	SourceLang string    `json:"source_lang"`
+	TargetLang string    `json:"target_lang"`
+	Provider   string    `json:"provider,omitempty"`
+	Model      string    `json:"model,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// Match is a stored segment similar to the text looked up.
+type Match struct {
+	Segment
+	Score float64 `json:"score"` // similarity in (0, 1]; 1 is an exact match
+}
+
+// Memory is a translation memory backed by BadgerDB.
+type Memory struct {
+	db *badger.DB
+}
+
+// Open opens the translation memory at the given path.
+func Open(path string) (*Memory, error) {
+	opts := badger.DefaultOptions(path)
+	opts.Logger = nil // Disable BadgerDB internal logging
+
+	db, err := badger.Open(opts)
+	if err != nil {
+		return nil, fmt.Errorf("open badger: %w", err)
+	}
+	return &Memory{db: db}, nil
+}
+
+// Close closes the translation memory database.
+func (m *Memory) Close() error {
+	return m.db.Close()
+}
+
+// Add stores the segment, replacing any earlier translation of the same
+// source text for the same language pair.
+func (m *Memory) Add(s Segment) error {
+	if strings.TrimSpace(s.Source) == "" || strings.TrimSpace(s.Target) == "" {
+		return fmt.Errorf("segment source and target required")
+	}
+	if s.CreatedAt.IsZero() {
+		s.CreatedAt = time.Now()
+	}
+
+	data, err := json.Marshal(s)
+	if err != nil {
+		return fmt.Errorf("marshal segment: %w", err)
+	}
+
+	return m.db.Update(func(txn *badger.Txn) error {
+		return txn.Set(segmentKey(s.SourceLang, s.TargetLang, s.Source), data)
+	})
+}
+
+// Lookup returns up to limit stored segments for the language pair whose
+// source is at least minScore similar to text, best first. Ties go to the
+// most recent segment. A minScore of 0 uses DefaultMinScore.
+func (m *Memory) Lookup(text, sourceLang, targetLang string, limit int, minScore float64) ([]Match, error) {
+	if minScore <= 0 {
+		minScore = DefaultMinScore
+	}
+	query := []rune(normalize(text))
+	if len(query) == 0 || limit <= 0 {
+		return nil, nil
+	}
+
+	var matches []Match
+	err := m.db.View(func(txn *badger.Txn) error {
+		it := txn.NewIterator(badger.DefaultIteratorOptions)
+		defer it.Close()
+
+		prefix := pairPrefix(sourceLang, targetLang)
+		for it.Seek(prefix); it.ValidForPrefix(prefix); it.Next() {
+			var s Segment
+			if err := it.Item().Value(func(val []byte) error {
+				return json.Unmarshal(val, &s)
+			}); err != nil {
+				return fmt.Errorf("unmarshal segment: %w", err)
+			}
+
+			if score := similarity(query, []rune(normalize(s.Source)), minScore); score >= minScore {
+				matches = append(matches, Match{Segment: s, Score: score})
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	slices.SortFunc(matches, func(a, b Match) int {
+		if c := cmp.Compare(b.Score, a.Score); c != 0 {
+			return c
+		}
+		return b.CreatedAt.Compare(a.CreatedAt)
+	})
+	if len(matches) > limit {
+		matches = matches[:limit]
+	}
+	return matches, nil
+}
+
+// pairPrefix returns the key prefix of segments for a language pair.
+func pairPrefix(sourceLang, targetLang string) []byte {
+	return []byte("seg\x00" + sourceLang + "\x00" + targetLang + "\x00")
+}
+
+// segmentKey identifies a source text within its language pair.
+func segmentKey(sourceLang, targetLang, source string) []byte {
+	hash := sha256.Sum256([]byte(normalize(source)))
+	return append(pairPrefix(sourceLang, targetLang), hex.EncodeToString(hash[:])...)
+}
+
+// SameSource reports whether a and b are the same source text, ignoring
+// formatting differences.
+func SameSource(a, b string) bool {
+	return normalize(a) == normalize(b)
+}
+
+// normalize applies NFC and collapses whitespace so that formatting
+// differences don't count as edits.
+func normalize(s string) string {
+	return strings.Join(strings.Fields(norm.NFC.String(s)), " ")
+}
@@ -0,0 +1,106 @@
+package tm
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestLevenshtein(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"", "", 0},
+		{"kitten", "sitting", 3},
+		{"Hello", "hello", 0},
+		{"你好世界", "你好", 2},
+		{"flaw", "lawn", 2},
+	}
+
+	for _, tt := range tests {
+		if got := levenshtein([]rune(tt.a), []rune(tt.b)); got != tt.want {
+			t.Errorf("levenshtein(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestSimilarityFilters(t *testing.T) {
+	// The filters must never reject a pair whose score reaches minScore.
+	pairs := [][2]string{
+		{"Click Save to keep your changes", "Click Save to keep the changes"},
+		{"The quick brown fox jumps over the lazy dog", "The quick brown fox jumped over a lazy dog"},
+		{"请点击保存按钮", "请点击保存键"},
+		{"abcdefghij", "abcdefghiX"},
+		{"short", "shirt"},
+	}
+	for _, p := range pairs {
+		a, b := []rune(p[0]), []rune(p[1])
+		exact := 1 - float64(levenshtein(a, b))/float64(max(len(a), len(b)))
+		for _, minScore := range []float64{0.5, 0.6, 0.7, 0.8, 0.9} {
+			got := similarity(a, b, minScore)
+			if exact >= minScore && got != exact {
+				t.Errorf("similarity(%q, %q, %v) = %v, want %v", p[0], p[1], minScore, got, exact)
+			}
+		}
+	}
+
+	if got := similarity([]rune("completely different"), []rune("nothing alike here"), 0.7); got >= 0.7 {
+		t.Errorf("dissimilar strings scored %v", got)
+	}
+}
+
+func TestLookup(t *testing.T) {
+	m, err := Open(filepath.Join(t.TempDir(), "tm"))
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	defer m.Close()
+
+	now := time.Now()
+	for _, s := range []Segment{
+		{Source: "Click Save to keep your changes.", Target: "点击保存以保留更改。", SourceLang: "en", TargetLang: "zh", CreatedAt: now},
+		{Source: "Click Cancel to discard your changes.", Target: "点击取消以放弃更改。", SourceLang: "en", TargetLang: "zh", CreatedAt: now},
+		{Source: "The weather is nice today.", Target: "今天天气很好。", SourceLang: "en", TargetLang: "zh", CreatedAt: now},
+		{Source: "Click Save to keep your changes.", Target: "Cliquez sur Enregistrer.", SourceLang: "en", TargetLang: "fr", CreatedAt: now},
+	} {
+		if err := m.Add(s); err != nil {
+			t.Fatalf("add: %v", err)
+		}
+	}
+
+	matches, err := m.Lookup("Click  Save to keep all your changes.", "en", "zh", 5, 0)
+	if err != nil {
+		t.Fatalf("lookup: %v", err)
+	}
+	if len(matches) == 0 {
+		t.Fatal("expected a fuzzy match")
+	}
+	if matches[0].Target != "点击保存以保留更改。" {
+		t.Errorf("best match = %q", matches[0].Target)
+	}
+	for _, mt := range matches {
+		if mt.TargetLang != "zh" {
+			t.Errorf("match from other language pair: %+v", mt)
+		}
+		if mt.Score < DefaultMinScore || mt.Score >= 1 {
+			t.Errorf("score %v out of range", mt.Score)
+		}
+	}
+
+	// Re-adding a source replaces its translation and matches exactly
+	if err := m.Add(Segment{Source: "The weather is nice today.", Target: "今天天气不错。", SourceLang: "en", TargetLang: "zh"}); err != nil {
+		t.Fatalf("add: %v", err)
+	}
+	matches, err = m.Lookup("the weather is nice today.", "en", "zh", 1, 0.9)
+	if err != nil {
+		t.Fatalf("lookup: %v", err)
+	}
+	if len(matches) != 1 || matches[0].Score != 1 || matches[0].Target != "今天天气不错。" {
+		t.Errorf("exact lookup = %+v", matches)
+	}
+
+	if matches, _ := m.Lookup("Something unrelated entirely", "en", "zh", 5, 0); len(matches) != 0 {
+		t.Errorf("unexpected matches: %+v", matches)
+	}
+}